@@ -0,0 +1,16 @@
+package tek
+
+// Algorithm is the way a Tagger scores tags.
+type Algorithm int
+
+const (
+	// AlgorithmTFIDF scores words and phrases by TF-IDF, reweighted by POS when there is a POS dictionary.
+	AlgorithmTFIDF Algorithm = iota
+	// AlgorithmTextRank ranks words by PageRank over their co-occurrence graph, see WithWindow.
+	// Adjacent top words are merged into phrases of up to 3 words, or WithMaxPhraseLen if set.
+	AlgorithmTextRank
+	// AlgorithmRAKE scores the runs of words between stop words and punctuation with RAKE.
+	AlgorithmRAKE
+	// AlgorithmYAKE scores keywords from casing, position, frequency, spread and context of their words, without a corpus.
+	AlgorithmYAKE
+)
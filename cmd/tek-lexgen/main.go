@@ -0,0 +1,310 @@
+// Command tek-lexgen builds the POS lexicon of a language, embedded by tek
+// from its lexicon/ directory, out of one or more sources:
+//
+//	tek-lexgen -lang id -kbbi pos_id.json -tsv extra.tsv -conllu id_gsd-ud-train.conllu
+//
+// -kbbi reads a JSON list of {"word", "type"} objects like pos_id.json,
+// -tsv reads rows of a word, its type and optionally its frequency, and
+// -conllu reads the lemmas and universal POS tags of a CoNLL-U treebank,
+// mapped to the types of tek. Each flag can be repeated. A word found several
+// times with the same type is a duplicate, one found with several types is a
+// conflict, and both are reported. A word keeps all its types, with how often
+// each was seen, the type seen most first, then the one of the source given first.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/didasy/tek"
+)
+
+// source is a lexicon file and the function reading it.
+type source struct {
+	path string
+	read func(io.Reader) ([]entry, error)
+}
+
+// entry is a word with its type, seen count times in a source.
+type entry struct {
+	word, typ string
+	count     float64
+}
+
+// sources collects the source flags in the order they are given.
+type sources struct {
+	list *[]source
+	read func(io.Reader) ([]entry, error)
+}
+
+func (s sources) String() string { return "" }
+
+func (s sources) Set(path string) error {
+	*s.list = append(*s.list, source{path, s.read})
+	return nil
+}
+
+func main() {
+	var list []source
+	lang := flag.String("lang", "", "code of the language, the lexicon is written to <out>/<lang>.tsv.gz")
+	out := flag.String("out", "lexicon", "directory to write the lexicon to")
+	verbose := flag.Bool("v", false, "list the duplicates as well as the conflicts")
+	flag.Var(sources{&list, readKBBI}, "kbbi", "KBBI dump, a JSON list of {\"word\", \"type\"} objects")
+	flag.Var(sources{&list, readTSV}, "tsv", "rows of a word and its type, separated by a tab")
+	flag.Var(sources{&list, readCoNLLU}, "conllu", "CoNLL-U treebank")
+	flag.Parse()
+	if *lang == "" || len(list) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	lex := newLexicon()
+	for i, src := range list {
+		f, err := os.Open(src.path)
+		if err != nil {
+			fail(err)
+		}
+		entries, err := src.read(f)
+		f.Close()
+		if err != nil {
+			fail(fmt.Errorf("%s: %v", src.path, err))
+		}
+		for _, e := range entries {
+			lex.add(e, i)
+		}
+	}
+
+	pos := lex.resolve()
+	duplicates, conflicts := lex.report(os.Stderr, list, *verbose)
+	path := filepath.Join(*out, *lang+".tsv.gz")
+	if err := write(path, pos); err != nil {
+		fail(err)
+	}
+	fmt.Fprintf(os.Stderr, "%s: %d words, %d duplicates, %d conflicts\n", path, len(pos), duplicates, conflicts)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "tek-lexgen:", err)
+	os.Exit(1)
+}
+
+func write(path string, pos []*tek.Vocab) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := tek.WriteLexicon(f, pos); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// lexicon merges the entries of every source.
+type lexicon struct {
+	words map[string]*word
+}
+
+// word is what the sources say about a word.
+type word struct {
+	// how often each type was seen, and the first source it was seen in
+	counts  map[string]float64
+	sources map[string]int
+	// number of entries of the word
+	entries int
+}
+
+func newLexicon() *lexicon {
+	return &lexicon{words: make(map[string]*word)}
+}
+
+func (lex *lexicon) add(e entry, src int) {
+	w := lex.words[e.word]
+	if w == nil {
+		w = &word{counts: make(map[string]float64), sources: make(map[string]int)}
+		lex.words[e.word] = w
+	}
+	if _, ok := w.sources[e.typ]; !ok {
+		w.sources[e.typ] = src
+	}
+	w.counts[e.typ] += e.count
+	w.entries++
+}
+
+// types returns the types of w, the one seen most first.
+func (w *word) types() []string {
+	types := make([]string, 0, len(w.counts))
+	for typ := range w.counts {
+		types = append(types, typ)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		a, b := types[i], types[j]
+		if w.counts[a] != w.counts[b] {
+			return w.counts[a] > w.counts[b]
+		}
+		if w.sources[a] != w.sources[b] {
+			return w.sources[a] < w.sources[b]
+		}
+		return a < b
+	})
+	return types
+}
+
+// resolve returns the words with their types, sorted by word.
+func (lex *lexicon) resolve() []*tek.Vocab {
+	pos := make([]*tek.Vocab, 0, len(lex.words))
+	for _, name := range lex.sorted() {
+		w := lex.words[name]
+		types := w.types()
+		vocab := &tek.Vocab{Id: len(pos) + 1, Word: name, Type: types[0]}
+		if len(types) > 1 {
+			for _, typ := range types {
+				vocab.Types = append(vocab.Types, tek.POSType{Type: typ, Freq: w.counts[typ]})
+			}
+		}
+		pos = append(pos, vocab)
+	}
+	return pos
+}
+
+func (lex *lexicon) sorted() []string {
+	words := make([]string, 0, len(lex.words))
+	for w := range lex.words {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// report writes the conflicts, and the duplicates if verbose, to w and returns how many there are.
+// A word is a duplicate when it has more entries than types.
+func (lex *lexicon) report(w io.Writer, list []source, verbose bool) (duplicates, conflicts int) {
+	for _, name := range lex.sorted() {
+		wd := lex.words[name]
+		types := wd.types()
+		if wd.entries > len(types) {
+			duplicates++
+			if verbose {
+				fmt.Fprintf(w, "duplicate: %s, %d entries\n", name, wd.entries)
+			}
+		}
+		if len(types) > 1 {
+			conflicts++
+			parts := make([]string, len(types))
+			for i, typ := range types {
+				parts[i] = fmt.Sprintf("%s x%g (%s)", typ, wd.counts[typ], list[wd.sources[typ]].path)
+			}
+			fmt.Fprintf(w, "conflict: %s: %s\n", name, strings.Join(parts, ", "))
+		}
+	}
+	return duplicates, conflicts
+}
+
+func readKBBI(r io.Reader) ([]entry, error) {
+	pos, err := tek.LoadPOSLexicon(r, tek.FormatJSON)
+	if err != nil {
+		return nil, err
+	}
+	return vocabEntries(pos), nil
+}
+
+func readTSV(r io.Reader) ([]entry, error) {
+	pos, err := tek.LoadPOSLexicon(r, tek.FormatTSV)
+	if err != nil {
+		return nil, err
+	}
+	return vocabEntries(pos), nil
+}
+
+func vocabEntries(pos []*tek.Vocab) []entry {
+	entries := make([]entry, 0, len(pos))
+	for _, vocab := range pos {
+		word := strings.ToLower(strings.TrimSpace(vocab.Word))
+		if word == "" {
+			continue
+		}
+		types := vocab.Types
+		if len(types) == 0 {
+			types = []tek.POSType{{Type: vocab.Type}}
+		}
+		for _, t := range types {
+			// an entry without a frequency counts once
+			if t.Type != "" {
+				entries = append(entries, entry{word, t.Type, math.Max(t.Freq, 1)})
+			}
+		}
+	}
+	return entries
+}
+
+// universalTypes maps the universal POS tags of CoNLL-U to the types of tek,
+// tags that are left out aren't words worth weighting, like PUNCT.
+var universalTypes = map[string]string{
+	"NOUN":  tek.POSNoun,
+	"PROPN": tek.POSName,
+	"VERB":  tek.POSVerb,
+	"AUX":   tek.POSVerb,
+	"ADJ":   tek.POSAdjective,
+	"ADV":   tek.POSAdverb,
+	"NUM":   tek.POSNumeral,
+	"PRON":  tek.POSPronoun,
+	"DET":   tek.POSPronoun,
+	"ADP":   tek.POSPreposition,
+	"CCONJ": tek.POSConjunction,
+	"SCONJ": tek.POSConjunction,
+	"INTJ":  tek.POSInterjection,
+	"PART":  tek.POSOther,
+	"X":     tek.POSOther,
+}
+
+// readCoNLLU counts the lemma and universal POS tag of each word of a
+// treebank, skipping the ranges of multiword tokens and the empty nodes.
+func readCoNLLU(r io.Reader) ([]entry, error) {
+	counts := make(map[[2]string]float64)
+	var order [][2]string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		cols := strings.Split(text, "\t")
+		if len(cols) != 10 {
+			return nil, fmt.Errorf("line %d: want 10 columns, got %d", line, len(cols))
+		}
+		if strings.ContainsAny(cols[0], "-.") {
+			continue
+		}
+		typ, ok := universalTypes[cols[3]]
+		if !ok {
+			continue
+		}
+		lemma := cols[2]
+		if lemma == "_" || lemma == "" {
+			lemma = cols[1]
+		}
+		key := [2]string{strings.ToLower(lemma), typ}
+		if counts[key] == 0 {
+			order = append(order, key)
+		}
+		counts[key]++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	entries := make([]entry, len(order))
+	for i, key := range order {
+		entries[i] = entry{key[0], key[1], counts[key]}
+	}
+	return entries, nil
+}
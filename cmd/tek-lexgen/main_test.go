@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/didasy/tek"
+)
+
+const treebank = `# sent_id = 1
+# text = Rumah itu dibangun.
+1	Rumah	rumah	NOUN	_	_	3	nsubj	_	_
+2	itu	itu	DET	_	_	1	det	_	_
+3-4	dibangunnya	_	_	_	_	_	_	_	_
+3	dibangun	bangun	VERB	_	_	0	root	_	_
+4	nya	dia	PRON	_	_	3	obj	_	_
+4.1	ada	ada	VERB	_	_	_	_	_	_
+5	.	.	PUNCT	_	_	3	punct	_	_
+
+# sent_id = 2
+1	Bangun	bangun	VERB	_	_	0	root	_	_
+2	rumah	rumah	NOUN	_	_	1	obj	_	_
+`
+
+func TestReadCoNLLU(t *testing.T) {
+	entries, err := readCoNLLU(strings.NewReader(treebank))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []entry{{"rumah", tek.POSNoun, 2}, {"itu", tek.POSPronoun, 1}, {"bangun", tek.POSVerb, 2}, {"dia", tek.POSPronoun, 1}}
+	if len(entries) != len(want) {
+		t.Fatalf("got %v, want %v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d: got %v, want %v", i, entries[i], want[i])
+		}
+	}
+
+	if _, err := readCoNLLU(strings.NewReader("1\trumah\n")); err == nil {
+		t.Error("want an error for a row without 10 columns")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	kbbi, err := readKBBI(strings.NewReader(`[{"id":1,"word":"bangun","type":"adjektiva"},{"id":2,"word":"rumah","type":"nomina"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conllu, _ := readCoNLLU(strings.NewReader(treebank))
+	list := []source{{path: "kbbi.json"}, {path: "ud.conllu"}}
+	lex := newLexicon()
+	for _, e := range kbbi {
+		lex.add(e, 0)
+	}
+	for _, e := range conllu {
+		lex.add(e, 1)
+	}
+
+	var buf bytes.Buffer
+	duplicates, conflicts := lex.report(&buf, list, true)
+	if duplicates != 1 || conflicts != 1 {
+		t.Errorf("got %d duplicates and %d conflicts, want 1 and 1:\n%s", duplicates, conflicts, buf.String())
+	}
+	if want := "conflict: bangun: verba x2 (ud.conllu), adjektiva x1 (kbbi.json)\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("got report\n%s\nwant it to contain\n%s", buf.String(), want)
+	}
+
+	types := make(map[string]string)
+	for _, vocab := range lex.resolve() {
+		types[vocab.Word] = vocab.Type
+		if vocab.Word == "bangun" {
+			want := []tek.POSType{{Type: tek.POSVerb, Freq: 2}, {Type: tek.POSAdjective, Freq: 1}}
+			if len(vocab.Types) != 2 || vocab.Types[0] != want[0] || vocab.Types[1] != want[1] {
+				t.Errorf("bangun: got types %v, want %v", vocab.Types, want)
+			}
+		}
+	}
+	want := map[string]string{"bangun": tek.POSVerb, "dia": tek.POSPronoun, "itu": tek.POSPronoun, "rumah": tek.POSNoun}
+	if len(types) != len(want) {
+		t.Fatalf("got %v, want %v", types, want)
+	}
+	for word, typ := range want {
+		if types[word] != typ {
+			t.Errorf("%s: got %s, want %s", word, types[word], typ)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package tek
+
+import "errors"
+
+var (
+	// ErrEmptyInput is returned when the text to tag has no content.
+	ErrEmptyInput = errors.New("tek: empty input")
+	// ErrInvalidNum is returned when the number of requested tags is not positive.
+	ErrInvalidNum = errors.New("tek: number of tags must be positive")
+	// ErrUnsupportedLang is returned when a language has no stop words or POS data.
+	// Errors wrapping it include the offending language code.
+	ErrUnsupportedLang = errors.New("tek: unsupported language")
+	// ErrInvalidLang is returned when registering a language under an empty code or "auto".
+	ErrInvalidLang = errors.New("tek: invalid language code")
+	// ErrUnsupportedFormat is returned when saving or loading data in an unknown Format.
+	ErrUnsupportedFormat = errors.New("tek: unsupported format")
+)
@@ -0,0 +1,99 @@
+package tek
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Component is one part of the score of a tag.
+type Component struct {
+	// e.g. "count", "idf" or "pos"
+	Name  string
+	Value float64
+	// e.g. where the IDF comes from, or the POS type behind a boost
+	Detail string
+	// Value multiplies the score, a boost above 1 and a penalty below 1
+	Factor bool
+}
+
+func (c Component) String() string {
+	var s string
+	switch {
+	case !c.Factor:
+		s = c.Name + "=" + formatValue(c.Value)
+	case c.Value < 1:
+		s = "penalty " + c.Name + " x" + formatValue(c.Value)
+	default:
+		s = "boost " + c.Name + " x" + formatValue(c.Value)
+	}
+	if c.Detail != "" {
+		s += " (" + c.Detail + ")"
+	}
+	return s
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// Explanation is the breakdown of the score of a tag, set by WithExplain.
+type Explanation []Component
+
+// String returns the components on one line for logs, e.g.
+//
+//	count=8, df=7 (sentences), idf=3.488 (document), tf=0.03493, boost pos x4.5 (nama), score=0.5483
+func (e Explanation) String() string {
+	parts := make([]string, len(e))
+	for i, c := range e {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// explain returns how the score of tag was made with the settings of cfg.
+func (doc *document) explain(tag *Info, cfg *settings) Explanation {
+	var count, sentences float64
+	if stat := doc.index[tag.Stem]; stat != nil {
+		count, sentences = float64(stat.freq), float64(len(stat.sentences))
+	} else {
+		// a phrase found by the algorithm, but not indexed
+		count = float64(len(tag.Spans))
+		seen := make(map[int]bool)
+		for _, i := range tag.Sentences {
+			seen[i] = true
+		}
+		sentences = float64(len(seen))
+	}
+	e := Explanation{{Name: "count", Value: count}}
+
+	switch cfg.algorithm {
+	case AlgorithmTextRank:
+		e = append(e, Component{Name: "sentences", Value: sentences}, Component{Name: "rank", Value: tag.Score, Detail: "textrank"})
+	case AlgorithmRAKE:
+		e = append(e, Component{Name: "sentences", Value: sentences}, Component{Name: "degree/freq", Value: tag.Score, Detail: "rake"})
+	case AlgorithmYAKE:
+		e = append(e, Component{Name: "sentences", Value: sentences}, Component{Name: "yake", Value: 1 / tag.Score, Detail: "lower is better"})
+	default:
+		if model := cfg.idfModel; model != nil && model.Docs() > 0 {
+			e = append(e,
+				Component{Name: "df", Value: float64(model.DocFreq(tag.Stem)), Detail: fmt.Sprintf("of %d documents", model.Docs())},
+				Component{Name: "idf", Value: tag.Idf, Detail: "corpus"})
+		} else {
+			e = append(e,
+				Component{Name: "df", Value: sentences, Detail: "sentences"},
+				Component{Name: "idf", Value: tag.Idf, Detail: "document"})
+		}
+		e = append(e, Component{Name: "tf", Value: tag.Tf})
+		if n := phraseLen(tag.Stem); n > 1 {
+			e = append(e, Component{Name: "phrase length", Value: float64(n), Factor: true})
+		}
+		if lex := doc.lexicon(tag.Stem); lex != nil {
+			ctx := doc.posContext(tag.Stem)
+			if w := posWeight(tag.Stem, lex, doc.modifier(tag.Stem), ctx); w != 1 {
+				e = append(e, Component{Name: "pos", Value: w, Detail: posTypes(tag.Stem, lex, ctx), Factor: true})
+			}
+		}
+	}
+	return append(e, Component{Name: "score", Value: tag.Score})
+}
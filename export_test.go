@@ -0,0 +1,3 @@
+package tek
+
+var UnregisterLanguage = unregisterLanguage
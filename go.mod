@@ -1,6 +1,6 @@
 module github.com/didasy/tek
 
-go 1.12
+go 1.16
 
 require (
 	github.com/onsi/ginkgo v1.8.0
@@ -0,0 +1,118 @@
+package tek
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// Format is the encoding used to save and load data files.
+type Format int
+
+const (
+	FormatGob Format = iota
+	FormatJSON
+	// FormatTSV and FormatCSV are only read by LoadPOSLexicon
+	FormatTSV
+	FormatCSV
+)
+
+// IDFModel holds the document frequency of every term seen in a corpus.
+// A Tagger built WithIDFModel scores terms against the corpus instead of
+// against the sentences of the text being tagged.
+// It is safe for concurrent use, so a corpus can be trained in parallel.
+type IDFModel struct {
+	mu   sync.RWMutex
+	docs int
+	df   map[string]int
+}
+
+// idfModelData is the saved form of an IDFModel.
+type idfModelData struct {
+	Docs int            `json:"docs"`
+	DF   map[string]int `json:"df"`
+}
+
+// NewIDFModel returns an empty IDFModel.
+func NewIDFModel() *IDFModel {
+	return &IDFModel{df: make(map[string]int)}
+}
+
+// AddDocument counts a document made of terms, each distinct term is counted once.
+// A document without terms isn't counted, so empty texts don't lower every IDF.
+// Use Tagger.Train to add a raw text with the same tokenization used for tagging.
+func (m *IDFModel) AddDocument(terms []string) {
+	seen := make(map[string]bool, len(terms))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, term := range terms {
+		if term == "" || seen[term] {
+			continue
+		}
+		seen[term] = true
+		m.df[term]++
+	}
+	if len(seen) > 0 {
+		m.docs++
+	}
+}
+
+// Docs returns the number of documents in the model.
+func (m *IDFModel) Docs() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.docs
+}
+
+// DocFreq returns the number of documents containing term.
+func (m *IDFModel) DocFreq(term string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.df[term]
+}
+
+// IDF returns the smoothed inverse document frequency of term, log((N+1)/(df+1)) + 1.
+// Terms never seen in the corpus get the highest weight.
+func (m *IDFModel) IDF(term string) float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return math.Log(float64(m.docs+1)/float64(m.df[term]+1)) + 1
+}
+
+// Save writes the model to w in format f.
+func (m *IDFModel) Save(w io.Writer, f Format) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := idfModelData{Docs: m.docs, DF: m.df}
+	switch f {
+	case FormatGob:
+		return gob.NewEncoder(w).Encode(&data)
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(&data)
+	}
+	return fmt.Errorf("%w: %d", ErrUnsupportedFormat, f)
+}
+
+// LoadIDFModel reads a model written by Save in format f.
+func LoadIDFModel(r io.Reader, f Format) (*IDFModel, error) {
+	var data idfModelData
+	var err error
+	switch f {
+	case FormatGob:
+		err = gob.NewDecoder(r).Decode(&data)
+	case FormatJSON:
+		err = json.NewDecoder(r).Decode(&data)
+	default:
+		err = fmt.Errorf("%w: %d", ErrUnsupportedFormat, f)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data.DF == nil {
+		data.DF = make(map[string]int)
+	}
+	return &IDFModel{docs: data.Docs, df: data.DF}, nil
+}
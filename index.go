@@ -0,0 +1,357 @@
+package tek
+
+import "strings"
+
+// minPhraseFreq is how many times a multi-word phrase must occur to become a candidate.
+const minPhraseFreq = 2
+
+// termStat is what the index knows about a single term.
+type termStat struct {
+	// indices of the sentences containing the term, ascending and without duplicates
+	sentences []int
+	// number of occurrences in the whole text
+	freq int
+	// number of occurrences of each form grouped under the term by the stemmer
+	forms map[string]int
+	// the most frequent form, the first one seen wins a tie
+	form string
+	// occurrences of each form as written, inside a sentence and at its start
+	surfaces, startSurfaces map[string]int
+	// occurrences in each language
+	langs map[string]int
+}
+
+// document is a text split into sentences, with an inverted index from each
+// term to the sentences it appears in, so scoring never has to rescan the text.
+// Terms are indexed by their stem, a phrase by the stems of its words.
+type document struct {
+	sentences [][]string
+	// stems of the words of each sentence
+	keys [][]string
+	// the words of each sentence as written, keeping their case
+	surfaces [][]string
+	// whether a clause boundary follows each word
+	breaks [][]bool
+	// whether each word is a stop word in the language of its sentence
+	stops [][]bool
+	// language of each word
+	langs [][]string
+	// language of the text, and the resources of each language of its sentences
+	lang      string
+	resources map[string]*langResources
+	// kind of the terms that aren't KindWord
+	kinds map[string]Kind
+	index map[string]*termStat
+	// multi-word phrases in the index, by first appearance
+	phrases []string
+	// total number of words in all sentences
+	words int
+	// every sentence, including the repeated ones, with the position of its words
+	all []locatedSentence
+}
+
+// locatedSentence is the stems of a sentence with the spans of its words in
+// the text and its index among the sentences of the text.
+type locatedSentence struct {
+	keys  []string
+	spans []Span
+	index int
+}
+
+// buildIndex indexes the words of the sentences, and the phrases of up to
+// maxPhraseLen words that don't contain a stop word or cross a clause, in a
+// single pass.
+func (doc *document) buildIndex(maxPhraseLen int) {
+	doc.index = make(map[string]*termStat)
+	for i, sen := range doc.sentences {
+		stems := doc.keys[i]
+		for j, word := range sen {
+			doc.add(stems[j], word, doc.surfaces[i][j], doc.langs[i][j], j == 0, i)
+			if doc.stops[i][j] {
+				continue
+			}
+			// extend the phrase word by word until it hits a stop word or the end of the clause
+			for k := j + 1; k < len(sen) && k-j < maxPhraseLen && !doc.stops[i][k] && !doc.breaks[i][k-1]; k++ {
+				phrase := strings.Join(stems[j:k+1], " ")
+				if doc.add(phrase, strings.Join(sen[j:k+1], " "), strings.Join(doc.surfaces[i][j:k+1], " "), doc.langs[i][j], j == 0, i) {
+					doc.phrases = append(doc.phrases, phrase)
+				}
+			}
+		}
+		doc.words += len(sen)
+	}
+}
+
+// add counts an occurrence of form, written as surface in language lang, under term in sentence i and reports whether term is new.
+// start tells whether the occurrence starts the sentence.
+func (doc *document) add(term string, form string, surface string, lang string, start bool, i int) bool {
+	stat := doc.index[term]
+	isNew := stat == nil
+	if isNew {
+		stat = &termStat{forms: make(map[string]int, 1), form: form, surfaces: make(map[string]int, 1), startSurfaces: make(map[string]int), langs: make(map[string]int, 1)}
+		doc.index[term] = stat
+	}
+	stat.langs[lang]++
+	if start {
+		stat.startSurfaces[surface]++
+	} else {
+		stat.surfaces[surface]++
+	}
+	if n := len(stat.sentences); n == 0 || stat.sentences[n-1] != i {
+		stat.sentences = append(stat.sentences, i)
+	}
+	stat.freq++
+	stat.forms[form]++
+	if stat.forms[form] > stat.forms[stat.form] {
+		stat.form = form
+	}
+	return isNew
+}
+
+// candidateWords returns the stems of the words that aren't stop words, by first appearance.
+func (doc *document) candidateWords() []string {
+	seen := make(map[string]bool)
+	var res []string
+	for i, keys := range doc.keys {
+		for j, stem := range keys {
+			if !doc.stops[i][j] && !seen[stem] {
+				seen[stem] = true
+				res = append(res, stem)
+			}
+		}
+	}
+	return res
+}
+
+// language returns the language term is written in most, the
+// language of the text on a tie. A phrase that isn't indexed takes the
+// language of its first word.
+func (doc *document) language(term string) string {
+	stat := doc.index[term]
+	if stat == nil {
+		if words := strings.Fields(term); len(words) > 1 {
+			return doc.language(words[0])
+		}
+		return doc.lang
+	}
+	lang, n := "", 0
+	for l, m := range stat.langs {
+		if m > n || (m == n && l < lang) {
+			lang, n = l, m
+		}
+	}
+	if stat.langs[doc.lang] == n {
+		return doc.lang
+	}
+	return lang
+}
+
+// lexicon returns the POS dictionary of the language of term, if it has one.
+func (doc *document) lexicon(term string) lexicon {
+	if res := doc.resources[doc.language(term)]; res != nil {
+		return res.pos
+	}
+	return nil
+}
+
+// posContext returns how the words of term are written in the text, for the
+// POS hint of its language, or nil if the language has no hint.
+func (doc *document) posContext(term string) *posContext {
+	res := doc.resources[doc.language(term)]
+	if res == nil || res.posHint == nil {
+		return nil
+	}
+	return &posContext{
+		forms: func(stem string) map[string]int {
+			if stat := doc.index[stem]; stat != nil {
+				return stat.forms
+			}
+			return nil
+		},
+		hint: res.posHint,
+	}
+}
+
+// modifier returns the POS modifiers of the language of term.
+func (doc *document) modifier(term string) map[string]float64 {
+	if res := doc.resources[doc.language(term)]; res != nil {
+		return res.modifier
+	}
+	return nil
+}
+
+// hasPOS reports whether any language of the text has a POS dictionary.
+func (doc *document) hasPOS() bool {
+	for _, res := range doc.resources {
+		if res.pos != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// kind returns the Kind of term.
+func (doc *document) kind(term string) Kind {
+	return doc.kinds[term]
+}
+
+// form returns the most frequent form of term. A phrase that isn't in the
+// index is made of the forms of its words.
+func (doc *document) form(term string) string {
+	if stat := doc.index[term]; stat != nil {
+		return stat.form
+	}
+	if phraseLen(term) == 1 {
+		return term
+	}
+	words := strings.Fields(term)
+	for i, word := range words {
+		words[i] = doc.form(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// display returns the form of term as it is most often written inside a
+// sentence, so acronyms stay uppercase and names capitalized. A form only
+// seen at the start of sentences is taken as written there. A phrase that
+// isn't in the index is made of the display of its words.
+func (doc *document) display(term string) string {
+	stat := doc.index[term]
+	if stat == nil {
+		if phraseLen(term) == 1 {
+			return term
+		}
+		words := strings.Fields(term)
+		for i, word := range words {
+			words[i] = doc.display(word)
+		}
+		return strings.Join(words, " ")
+	}
+	if surface := mostCommonSurface(stat.surfaces, stat.form); surface != "" {
+		return surface
+	}
+	if surface := mostCommonSurface(stat.startSurfaces, stat.form); surface != "" {
+		return surface
+	}
+	return stat.form
+}
+
+// mostCommonSurface returns the most common of the surfaces of form, on a tie
+// the one sorting first, which is the one with more capitals.
+func mostCommonSurface(surfaces map[string]int, form string) string {
+	var res string
+	for surface, n := range surfaces {
+		if strings.ToLower(surface) != form {
+			continue
+		}
+		if m := surfaces[res]; res == "" || n > m || (n == m && surface < res) {
+			res = surface
+		}
+	}
+	return res
+}
+
+// sentencesOf returns the indices of the sentences containing term, which may be a phrase that isn't indexed.
+func (doc *document) sentencesOf(term string) []int {
+	if stat := doc.index[term]; stat != nil {
+		return stat.sentences
+	}
+	stems := strings.Fields(term)
+	var res []int
+	for i, keys := range doc.keys {
+	next:
+		for j := 0; j+len(stems) <= len(keys); j++ {
+			for k, stem := range stems {
+				if keys[j+k] != stem {
+					continue next
+				}
+			}
+			res = append(res, i)
+			break
+		}
+	}
+	return res
+}
+
+// occurrences returns the spans of term in the text and the index of the sentence of each.
+func (doc *document) occurrences(term string) ([]Span, []int) {
+	stems := strings.Fields(term)
+	var spans []Span
+	var sentences []int
+	for _, sen := range doc.all {
+	next:
+		for j := 0; j+len(stems) <= len(sen.keys); j++ {
+			for k, stem := range stems {
+				if sen.keys[j+k] != stem {
+					continue next
+				}
+			}
+			spans = append(spans, Span{sen.spans[j].Start, sen.spans[j+len(stems)-1].End})
+			sentences = append(sentences, sen.index)
+		}
+	}
+	return spans, sentences
+}
+
+// candidatePhrases returns the phrases frequent enough to be scored.
+func (doc *document) candidatePhrases() []string {
+	var res []string
+	for _, phrase := range doc.phrases {
+		if doc.index[phrase].freq >= minPhraseFreq {
+			res = append(res, phrase)
+		}
+	}
+	return res
+}
+
+// phraseLen returns the number of words in term.
+func phraseLen(term string) int {
+	return strings.Count(term, " ") + 1
+}
+
+// isComponent reports whether part is a word or a shorter phrase inside phrase.
+func isComponent(part, phrase string) bool {
+	return part != phrase && strings.Contains(" "+phrase+" ", " "+part+" ")
+}
+
+// selectTags returns the first num tags of the sorted termsInfo. If suppress is
+// set, a word or phrase is left out once a longer phrase containing it is chosen,
+// and a phrase replaces its words chosen before it. A phrase containing a
+// better scored phrase is left out as well.
+func selectTags(termsInfo []*Info, num int, suppress bool) []*Info {
+	if !suppress {
+		// out of range error guard
+		if num >= len(termsInfo) {
+			num = len(termsInfo)
+		}
+		result := make([]*Info, num)
+		copy(result, termsInfo[:num])
+		return result
+	}
+
+	result := make([]*Info, 0, num)
+	for _, info := range termsInfo {
+		if len(result) == num {
+			break
+		}
+		covered := false
+		for _, chosen := range result {
+			if isComponent(info.Stem, chosen.Stem) || (phraseLen(chosen.Stem) > 1 && isComponent(chosen.Stem, info.Stem)) {
+				covered = true
+				break
+			}
+		}
+		if covered {
+			continue
+		}
+		// drop the components chosen before their phrase
+		kept := result[:0]
+		for _, chosen := range result {
+			if !isComponent(chosen.Stem, info.Stem) {
+				kept = append(kept, chosen)
+			}
+		}
+		result = append(kept, info)
+	}
+	return result
+}
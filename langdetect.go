@@ -0,0 +1,148 @@
+package tek
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// profileSize is how many of the most frequent n-grams make a profile.
+	profileSize = 400
+	// maxNgram is the longest n-gram of a profile.
+	maxNgram = 4
+	// detectMaxBytes is how much of a text is read to detect its language.
+	detectMaxBytes = 20000
+)
+
+// profile maps the most frequent n-grams of a text to their rank.
+type profile map[string]int
+
+var (
+	langProfiles     map[string]profile
+	langProfilesOnce sync.Once
+)
+
+// profiles returns the profile of each language of langSamples, built the first time it is needed.
+func profiles() map[string]profile {
+	langProfilesOnce.Do(func() {
+		langProfiles = make(map[string]profile, len(langSamples))
+		for lang, sample := range langSamples {
+			langProfiles[lang] = newProfile(sample)
+		}
+	})
+	return langProfiles
+}
+
+// newProfile ranks the n-grams of the words of text, padded with '_' so
+// the start and end of words count, as in Cavnar and Trenkle's N-Gram-Based
+// Text Categorization.
+func newProfile(text string) profile {
+	counts := make(map[string]int)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, word := range words {
+		runes := []rune("_" + word + "_")
+		for n := 1; n <= maxNgram; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				gram := string(runes[i : i+n])
+				if gram != "_" {
+					counts[gram]++
+				}
+			}
+		}
+	}
+	grams := make([]string, 0, len(counts))
+	for gram := range counts {
+		grams = append(grams, gram)
+	}
+	sort.Slice(grams, func(i, j int) bool {
+		if counts[grams[i]] != counts[grams[j]] {
+			return counts[grams[i]] > counts[grams[j]]
+		}
+		return grams[i] < grams[j]
+	})
+	if len(grams) > profileSize {
+		grams = grams[:profileSize]
+	}
+	p := make(profile, len(grams))
+	for i, gram := range grams {
+		p[gram] = i
+	}
+	return p
+}
+
+// distance is the out-of-place measure between the profile of a text and the profile of a language.
+func (p profile) distance(lang profile) int {
+	d := 0
+	for gram, rank := range p {
+		if r, ok := lang[gram]; ok {
+			if r > rank {
+				d += r - rank
+			} else {
+				d += rank - r
+			}
+		} else {
+			d += profileSize
+		}
+	}
+	return d
+}
+
+// DetectLang returns the language of text among "id", "en", "ms", "jv", "su",
+// "de", "fr", "es", "nl" and the languages registered with a Sample, with a confidence from 0 to 1 that grows with
+// how much closer the text is to that language than to the next one.
+// A text without letters gives an empty language.
+func DetectLang(text string) (string, float64) {
+	return detectLang(text, nil)
+}
+
+// detectLang is DetectLang among langs only, or all the languages if langs is empty.
+func detectLang(text string, langs []string) (string, float64) {
+	if len(text) > detectMaxBytes {
+		text = text[:detectMaxBytes]
+		for !utf8.ValidString(text) {
+			text = text[:len(text)-1]
+		}
+	}
+	p := newProfile(text)
+	if len(p) == 0 {
+		return "", 0
+	}
+	if len(langs) == 0 {
+		seen := make(map[string]bool)
+		for lang := range profiles() {
+			seen[lang] = true
+		}
+		for _, lang := range detectableLangs() {
+			seen[lang] = true
+		}
+		for lang := range seen {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+	}
+
+	best, bestDist, secondDist := "", -1, -1
+	for _, lang := range langs {
+		lp := profileOf(lang)
+		if lp == nil {
+			continue
+		}
+		d := p.distance(lp)
+		switch {
+		case bestDist < 0 || d < bestDist:
+			best, bestDist, secondDist = lang, d, bestDist
+		case secondDist < 0 || d < secondDist:
+			secondDist = d
+		}
+	}
+	if bestDist < 0 {
+		return "", 0
+	}
+	if secondDist <= 0 {
+		return best, 1
+	}
+	return best, 1 - float64(bestDist)/float64(secondDist)
+}
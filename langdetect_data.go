@@ -0,0 +1,140 @@
+package tek
+
+// Sample texts the n-gram profile of each language is built from, see DetectLang.
+// Everyday news and conversation, so the profiles hold the common words and endings.
+var langSamples = map[string]string{
+	"en": `The city council met on Tuesday to discuss the new budget for public transport, schools and hospitals.
+Most of the members agreed that the roads in the northern district need to be repaired before the winter comes.
+The mayor said that the government would spend more money on buses and trains, because many people cannot afford a car.
+Some residents were worried about the cost, while others thanked the council for listening to their complaints.
+After the meeting, a group of students gathered outside the building with signs asking for cheaper tickets.
+The weather has been cold and wet this week, and the forecast says that it will snow on the weekend.
+My brother works at a small bakery near the river, where he wakes up every morning before the sun rises.
+He told me that the shop is busy on Saturdays, when families come to buy bread, cakes and coffee.
+We have been friends with our neighbours for years, and they always invite us to their garden in the summer.
+The museum will open a new exhibition about the history of the region, with paintings, photographs and old letters.
+Scientists say that the climate is changing faster than expected, and that we should act now to protect the forests.
+The company announced that it had hired more workers than last year, which was good news for the town.
+Which book are you reading at the moment? I think you would enjoy the one about the journey across the mountains.
+They should have been here by now, but their train was late again, so we decided to wait at the station.`,
+
+	"id": `Dewan kota mengadakan rapat pada hari Selasa untuk membahas anggaran baru bagi transportasi umum, sekolah dan rumah sakit.
+Sebagian besar anggota setuju bahwa jalan di wilayah utara harus diperbaiki sebelum musim hujan tiba.
+Wali kota mengatakan bahwa pemerintah akan mengeluarkan lebih banyak uang untuk bus dan kereta, karena banyak orang tidak mampu membeli mobil.
+Beberapa warga khawatir tentang biayanya, sementara yang lain berterima kasih kepada dewan karena sudah mendengarkan keluhan mereka.
+Setelah rapat, sekelompok mahasiswa berkumpul di luar gedung sambil membawa poster yang meminta tiket lebih murah.
+Cuaca minggu ini dingin dan basah, dan ramalan cuaca menyebutkan bahwa hujan deras akan turun pada akhir pekan.
+Kakak saya bekerja di sebuah toko roti kecil dekat sungai, dan dia bangun setiap pagi sebelum matahari terbit.
+Dia bilang tokonya ramai pada hari Sabtu, ketika keluarga datang untuk membeli roti, kue dan kopi.
+Kami sudah lama berteman dengan tetangga kami, dan mereka selalu mengundang kami ke kebun mereka.
+Museum itu akan membuka pameran baru tentang sejarah daerah ini, dengan lukisan, foto dan surat-surat lama.
+Para ilmuwan mengatakan bahwa iklim berubah lebih cepat dari perkiraan, dan kita harus bertindak sekarang untuk melindungi hutan.
+Perusahaan itu mengumumkan bahwa mereka sudah merekrut lebih banyak karyawan daripada tahun lalu, sebuah kabar baik bagi kota ini.
+Polisi masih menyelidiki kasus tersebut dan belum bisa memberikan keterangan kepada wartawan di kantornya.
+Buku apa yang sedang kamu baca? Saya rasa kamu akan suka buku tentang perjalanan melintasi pegunungan.
+Mereka seharusnya sudah sampai, tetapi keretanya terlambat lagi, jadi kami memutuskan untuk menunggu saja di stasiun.`,
+
+	"ms": `Majlis bandaraya bermesyuarat pada hari Selasa bagi membincangkan belanjawan baharu untuk pengangkutan awam, sekolah dan hospital.
+Kebanyakan ahli bersetuju bahawa jalan raya di kawasan utara perlu dibaiki sebelum musim tengkujuh bermula.
+Datuk bandar berkata kerajaan akan membelanjakan lebih banyak wang bagi bas dan kereta api, kerana ramai orang tidak mampu membeli kereta.
+Sesetengah penduduk bimbang tentang kosnya, manakala yang lain berterima kasih kepada majlis kerana sudi mendengar aduan mereka.
+Selepas mesyuarat itu, sekumpulan pelajar universiti berhimpun di luar bangunan sambil membawa sepanduk meminta tiket yang lebih murah.
+Cuaca minggu ini sejuk dan lembap, dan ramalan cuaca menyatakan hujan lebat akan turun pada hujung minggu.
+Abang saya bekerja di sebuah kedai roti kecil berhampiran sungai, dan beliau bangun setiap pagi sebelum matahari terbit.
+Beliau memberitahu saya kedai itu sibuk pada hari Sabtu, apabila keluarga datang untuk membeli roti, kek dan kopi.
+Kami sudah lama berkawan dengan jiran kami, dan mereka sentiasa menjemput kami ke kebun mereka.
+Muzium itu akan membuka pameran baharu mengenai sejarah negeri ini, dengan lukisan, gambar dan surat-surat lama.
+Para saintis berkata iklim berubah lebih cepat daripada jangkaan, dan kita perlu bertindak sekarang bagi melindungi hutan.
+Syarikat itu mengumumkan bahawa ia telah mengambil lebih ramai pekerja berbanding tahun lepas, satu berita baik bagi pekan ini.
+Pihak polis masih menyiasat kes tersebut dan belum boleh memberikan sebarang kenyataan kepada pemberita di pejabatnya.
+Buku apakah yang sedang awak baca? Saya rasa awak akan suka buku tentang perjalanan merentasi banjaran gunung.
+Mereka sepatutnya sudah sampai, tetapi kereta api mereka lewat lagi, jadi kami memutuskan untuk menunggu sahaja di stesen.`,
+
+	"jv": `Dewan kutha nganakake rapat ing dina Selasa kanggo ngrembug anggaran anyar kanggo kendaraan umum, sekolahan lan rumah sakit.
+Akeh-akehe anggota sarujuk yen dalan ing wilayah lor kudu didandani sadurunge mangsa rendheng teka.
+Pak wali kutha ngendika yen pamarentah bakal ngetokake dhuwit luwih akeh kanggo bis lan sepur, amarga akeh wong sing ora kuwat tuku mobil.
+Sawetara warga padha kuwatir babagan ragade, dene liyane matur nuwun marang dewan amarga wis gelem ngrungokake keluhane.
+Sawise rapat, sakelompok mahasiswa padha nglumpuk ing njaba gedhong karo nggawa tulisan sing njaluk karcis luwih murah.
+Hawane minggu iki adhem lan teles, lan ramalan cuaca ngandhakake yen bakal udan deres ing pungkasan minggu.
+Kangmasku nyambut gawe ing toko roti cilik cedhak kali, lan dheweke tangi saben esuk sadurunge srengenge njedhul.
+Dheweke kandha yen tokone rame ing dina Setu, nalika kulawarga padha teka arep tuku roti, jajanan lan kopi.
+Aku lan tanggaku wis suwe kekancan, lan dheweke tansah ngajak aku dolan menyang kebone.
+Museum kuwi bakal mbukak pameran anyar babagan sejarah dhaerah iki, ana lukisan, foto lan layang-layang lawas.
+Para ahli ngendika yen iklim owah luwih cepet tinimbang sing dikira, mula awake dhewe kudu tumindak saiki kanggo njaga alas.
+Wong-wong ing desa iki padha seneng gotong royong, saben minggu padha ngresiki kali lan dalan bebarengan.
+Simbah kerep crita babagan jaman biyen, nalika durung ana listrik lan kabeh wong nganggo lampu senthir.
+Buku apa sing lagi kokwaca? Aku ngira kowe bakal seneng buku babagan lelungan nyabrang gunung-gunung kuwi.
+Dheweke mestine wis tekan, nanging sepure telat maneh, dadi aku mutusake ngenteni wae ing stasiun.`,
+
+	"su": `Dewan kota ngayakeun rapat dina poé Salasa pikeun nyawalakeun anggaran anyar keur angkutan umum, sakola jeung rumah sakit.
+Lolobana anggota satuju yén jalan di wewengkon kalér kudu dioméan saméméh usum hujan datang.
+Wali kota nyarios yén pamaréntah baris ngaluarkeun leuwih loba duit pikeun beus jeung karéta, sabab loba jalma anu teu mampuh meuli mobil.
+Sababaraha warga hariwang ngeunaan biayana, ari nu séjénna nganuhunkeun ka dewan sabab geus daék ngadéngékeun keluhan maranéhna.
+Sanggeus rapat, sakelompok mahasiswa ngariung di luareun gedong bari mawa tulisan anu ménta karcis leuwih murah.
+Hawa minggu ieu tiis jeung baseuh, sarta ramalan cuaca nyebutkeun yén bakal hujan gedé dina ahir minggu.
+Lanceuk kuring digawé di toko roti leutik deukeut walungan, sarta manéhna hudang unggal isuk saméméh panonpoé bijil.
+Manéhna nyarita yén tokona ramé dina poé Saptu, nalika kulawarga daratang rék meuli roti, kuéh jeung kopi.
+Kuring jeung tatangga geus lila sosobatan, sarta maranéhna sok ngajak kuring ulin ka kebonna.
+Musieum éta baris muka paméran anyar ngeunaan sajarah wewengkon ieu, aya lukisan, poto jeung surat-surat heubeul.
+Para ahli nyebutkeun yén iklim robah leuwih gancang batan anu disangka, ku kituna urang kudu migawé ayeuna pikeun ngajaga leuweung.
+Urang lembur di dieu resep pisan gotong royong, unggal minggu sok ngabersihan walungan jeung jalan babarengan.
+Nini sok ngadongéng ngeunaan jaman baheula, basa can aya listrik sarta kabéh jalma maké lampu cempor.
+Buku naon anu keur dibaca ku anjeun? Sigana anjeun bakal resep kana buku ngeunaan lalampahan meuntas pagunungan.
+Maranéhna kuduna geus nepi, tapi karétana telat deui, jadi kuring mutuskeun pikeun ngadagoan baé di stasion.`,
+
+	"de": `Der Stadtrat traf sich am Dienstag, um den neuen Haushalt für den öffentlichen Verkehr, die Schulen und die Krankenhäuser zu besprechen.
+Die meisten Mitglieder waren sich einig, dass die Straßen im nördlichen Bezirk vor dem Winter repariert werden müssen.
+Der Bürgermeister sagte, dass die Regierung mehr Geld für Busse und Züge ausgeben werde, weil sich viele Menschen kein Auto leisten können.
+Einige Bewohner machten sich Sorgen über die Kosten, während andere dem Rat dankten, dass er ihre Beschwerden gehört hatte.
+Nach der Sitzung versammelte sich eine Gruppe von Studenten vor dem Gebäude mit Schildern, auf denen sie billigere Fahrkarten forderten.
+Das Wetter war diese Woche kalt und nass, und die Vorhersage sagt, dass es am Wochenende schneien wird.
+Mein Bruder arbeitet in einer kleinen Bäckerei in der Nähe des Flusses, wo er jeden Morgen vor Sonnenaufgang aufsteht.
+Er erzählte mir, dass der Laden am Samstag voll ist, wenn Familien kommen, um Brot, Kuchen und Kaffee zu kaufen.
+Wir sind seit Jahren mit unseren Nachbarn befreundet, und sie laden uns im Sommer immer in ihren Garten ein.
+Das Museum wird eine neue Ausstellung über die Geschichte der Region eröffnen, mit Gemälden, Fotografien und alten Briefen.
+Wissenschaftler sagen, dass sich das Klima schneller verändert als erwartet, und dass wir jetzt handeln sollten, um die Wälder zu schützen.
+Welches Buch liest du gerade? Ich glaube, dir würde das Buch über die Reise durch die Berge gefallen.
+Sie hätten längst hier sein sollen, aber ihr Zug hatte wieder Verspätung, also haben wir beschlossen, am Bahnhof zu warten.`,
+
+	"fr": `Le conseil municipal s'est réuni mardi pour discuter du nouveau budget des transports publics, des écoles et des hôpitaux.
+La plupart des membres étaient d'accord pour dire que les routes du quartier nord doivent être réparées avant l'arrivée de l'hiver.
+Le maire a déclaré que le gouvernement dépenserait plus d'argent pour les bus et les trains, parce que beaucoup de gens ne peuvent pas se payer une voiture.
+Certains habitants s'inquiétaient du coût, tandis que d'autres ont remercié le conseil d'avoir écouté leurs plaintes.
+Après la réunion, un groupe d'étudiants s'est rassemblé devant le bâtiment avec des pancartes demandant des billets moins chers.
+Il a fait froid et humide cette semaine, et la météo annonce qu'il neigera pendant le week-end.
+Mon frère travaille dans une petite boulangerie près de la rivière, où il se lève chaque matin avant le lever du soleil.
+Il m'a dit que la boutique est pleine le samedi, quand les familles viennent acheter du pain, des gâteaux et du café.
+Nous sommes amis avec nos voisins depuis des années, et ils nous invitent toujours dans leur jardin en été.
+Le musée ouvrira une nouvelle exposition sur l'histoire de la région, avec des peintures, des photographies et de vieilles lettres.
+Les scientifiques disent que le climat change plus vite que prévu, et que nous devons agir maintenant pour protéger les forêts.
+Quel livre est-ce que tu lis en ce moment? Je pense que tu aimerais celui qui raconte le voyage à travers les montagnes.
+Ils auraient dû être là depuis longtemps, mais leur train était encore en retard, alors nous avons décidé d'attendre à la gare.`,
+
+	"es": `El ayuntamiento se reunió el martes para hablar del nuevo presupuesto para el transporte público, las escuelas y los hospitales.
+La mayoría de los miembros estuvo de acuerdo en que las carreteras del distrito norte deben repararse antes de que llegue el invierno.
+El alcalde dijo que el gobierno gastaría más dinero en autobuses y trenes, porque mucha gente no puede permitirse un coche.
+Algunos vecinos estaban preocupados por el coste, mientras que otros agradecieron al ayuntamiento por escuchar sus quejas.
+Después de la reunión, un grupo de estudiantes se juntó frente al edificio con carteles que pedían billetes más baratos.
+Esta semana ha hecho frío y ha llovido mucho, y el pronóstico dice que va a nevar durante el fin de semana.
+Mi hermano trabaja en una pequeña panadería cerca del río, donde se levanta todas las mañanas antes de que salga el sol.
+Me contó que la tienda está llena los sábados, cuando las familias vienen a comprar pan, pasteles y café.
+Somos amigos de nuestros vecinos desde hace años, y siempre nos invitan a su jardín en verano.
+El museo abrirá una nueva exposición sobre la historia de la región, con pinturas, fotografías y cartas antiguas.
+Los científicos dicen que el clima está cambiando más rápido de lo esperado, y que debemos actuar ahora para proteger los bosques.
+¿Qué libro estás leyendo ahora? Creo que te gustaría el que cuenta el viaje a través de las montañas.
+Ya deberían haber llegado, pero su tren volvió a retrasarse, así que decidimos esperar en la estación.`,
+
+	"nl": `De gemeenteraad kwam dinsdag bijeen om de nieuwe begroting voor het openbaar vervoer, de scholen en de ziekenhuizen te bespreken.
+De meeste leden waren het erover eens dat de wegen in het noordelijke district voor de winter moeten worden gerepareerd.
+De burgemeester zei dat de regering meer geld zou uitgeven aan bussen en treinen, omdat veel mensen zich geen auto kunnen veroorloven.
+Sommige bewoners maakten zich zorgen over de kosten, terwijl anderen de raad bedankten voor het luisteren naar hun klachten.
+Na de vergadering verzamelde een groep studenten zich voor het gebouw met borden waarop om goedkopere kaartjes werd gevraagd.
+Het weer was deze week koud en nat, en volgens de voorspelling gaat het in het weekend sneeuwen.
+Mijn broer werkt in een kleine bakkerij bij de rivier, waar hij elke ochtend opstaat voordat de zon opkomt.
+Hij vertelde me dat de winkel op zaterdag druk is, wanneer gezinnen brood, taart en koffie komen kopen.
+We zijn al jaren bevriend met onze buren, en ze nodigen ons in de zomer altijd uit in hun tuin.
+Het museum opent een nieuwe tentoonstelling over de geschiedenis van de streek, met schilderijen, foto's en oude brieven.
+Wetenschappers zeggen dat het klimaat sneller verandert dan verwacht, en dat we nu moeten handelen om de bossen te beschermen.
+Welk boek ben je nu aan het lezen? Ik denk dat je het boek over de reis door de bergen mooi zou vinden.
+Ze hadden hier allang moeten zijn, maar hun trein had weer vertraging, dus besloten we op het station te wachten.`,
+}
@@ -0,0 +1,121 @@
+package tek_test
+
+import (
+	"context"
+
+	. "github.com/didasy/tek"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Language detection", func() {
+
+	Describe("Detecting the language of a text", func() {
+		It("Should tell the supported languages apart", func() {
+			texts := map[string]string{
+				"id": "Pemerintah provinsi akan membangun lebih banyak rumah murah bagi warga di kota ini, kata gubernur kemarin.",
+				"ms": "Kerajaan negeri akan membina lebih banyak rumah mampu milik bagi rakyat di bandar ini, kata menteri besar semalam.",
+				"jv": "Aku wis ngomong marang kowe yen dina iki ora bisa teka amarga lagi lara.",
+				"su": "Kuring geus ngomong ka anjeun yén poé ieu teu bisa datang sabab keur gering.",
+				"en": "I told you that I could not come today because I am sick.",
+				"de": "Ich habe dir gesagt, dass ich heute nicht kommen kann, weil ich krank bin.",
+				"fr": "Je t'ai dit que je ne pouvais pas venir aujourd'hui parce que je suis malade.",
+				"es": "Te dije que no podía venir hoy porque estoy enfermo.",
+				"nl": "Ik heb je gezegd dat ik vandaag niet kan komen omdat ik ziek ben.",
+			}
+			for lang, text := range texts {
+				detected, confidence := DetectLang(text)
+				Expect(detected).To(Equal(lang), text)
+				Expect(confidence).To(BeNumerically(">", 0))
+				Expect(confidence).To(BeNumerically("<=", 1))
+			}
+		})
+
+		It("Should detect nothing without letters", func() {
+			lang, confidence := DetectLang("12 + 30 = 42")
+			Expect(lang).To(BeEmpty())
+			Expect(confidence).To(BeZero())
+		})
+	})
+
+	Describe("Tagging with the language set to auto", func() {
+		It("Should use the resources of the detected language", func() {
+			tagger, err := New(WithLang("auto"))
+			Expect(err).To(BeNil())
+			Expect(tagger.Lang()).To(Equal("auto"))
+
+			res, err := tagger.Analyze(context.Background(), string(indonesian), 3)
+			Expect(err).To(BeNil())
+			Expect(res.Lang).To(Equal("id"))
+			Expect(res.Confidence).To(BeNumerically(">", 0))
+			id, _ := New(WithLang("id"))
+			Expect(terms(res.Tags)).To(Equal(terms(id.GetTags(string(indonesian), 3))))
+
+			res, err = tagger.Analyze(context.Background(), string(sample), 3)
+			Expect(err).To(BeNil())
+			Expect(res.Lang).To(Equal("en"))
+			en, _ := New(WithLang("en"))
+			Expect(terms(res.Tags)).To(Equal(terms(en.GetTags(string(sample), 3))))
+		})
+
+		It("Should tag short Indonesian close to Malay as Indonesian", func() {
+			tagger, _ := New(WithLang("auto"))
+			for _, text := range []string{
+				"Polisi menangkap pelaku pencurian di pasar.",
+				"Saya makan nasi goreng di rumah nenek kemarin sore bersama keluarga.",
+			} {
+				res, err := tagger.Analyze(context.Background(), text, 3)
+				Expect(err).To(BeNil())
+				Expect(res.Lang).To(Equal("id"), text)
+				Expect(res.Tags).NotTo(BeEmpty())
+			}
+		})
+
+		It("Should tag a text without letters as English", func() {
+			tagger, _ := New(WithLang("auto"))
+			res, err := tagger.Analyze(context.Background(), "12 + 30 = 42", 3)
+			Expect(err).To(BeNil())
+			Expect(res.Lang).To(Equal("en"))
+			Expect(res.Confidence).To(BeZero())
+		})
+
+		It("Should report the language that was set", func() {
+			tagger, _ := New(WithLang("id"))
+			res, err := tagger.Analyze(context.Background(), string(sample), 3)
+			Expect(err).To(BeNil())
+			Expect(res.Lang).To(Equal("id"))
+			Expect(res.Confidence).To(Equal(1.0))
+		})
+	})
+})
+
+var _ = Describe("Mixed language texts", func() {
+	text := `Presiden Joko Widodo menghadiri pertemuan para pemimpin ekonomi di Jakarta pada hari Senin. ` +
+		`"We are open for business and we welcome every investor," kata Jokowi di depan para pengusaha. ` +
+		`Pemerintah berjanji mempermudah perizinan bagi investor asing yang ingin membangun pabrik di Indonesia. ` +
+		`"This is the best time to invest in our country," tambahnya. Para pengusaha menyambut baik janji pemerintah tersebut. ` +
+		`Menurut Jokowi, perizinan akan selesai dalam tiga jam. “We will keep our promise,” ujarnya.`
+	english := []string{"we", "our", "and", "for", "this", "is", "the"}
+
+	It("Should let English function words through with one language", func() {
+		tagger, _ := New(WithLang("id"))
+		Expect(terms(tagger.GetTags(text, 15))).To(ContainElement("we"))
+	})
+
+	It("Should use the stop words of the language of each sentence", func() {
+		tagger, _ := New(WithLang("id"), WithMixedLang(true))
+		tags := tagger.GetTags(text, 1000)
+		langs := make(map[string]string)
+		for _, tag := range tags {
+			langs[tag.Term] = tag.Lang
+		}
+		for _, word := range english {
+			Expect(langs).NotTo(HaveKey(word))
+		}
+		Expect(langs).To(HaveKeyWithValue("country", "en"))
+		Expect(langs).To(HaveKeyWithValue("promise", "en"))
+		Expect(langs).To(HaveKeyWithValue("pengusaha", "id"))
+		Expect(langs).To(HaveKeyWithValue("jokowi", "id"))
+	})
+})
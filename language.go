@@ -0,0 +1,126 @@
+package tek
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// quoteMarks split a sentence into spans whose language is detected on their own,
+	// as quotes are where a text most often switches language.
+	quoteMarks = "\"“”«»"
+	// mixedMinLetters is how many letters a span needs for its own language to be detected.
+	mixedMinLetters = 20
+	// mixedMinConfidence is how sure the detection must be to leave the language of the text.
+	mixedMinConfidence = 0.05
+)
+
+// langResources are the stop words, stemmer, POS dictionary and modifiers a
+// run uses for the words of a language, with the stems found so far.
+type langResources struct {
+	lang     string
+	stopList *stopList
+	stemmer  Stemmer
+	pos      lexicon
+	posHint  func(form, stem string) string
+	modifier map[string]float64
+	stems    map[string]string
+}
+
+func newLangResources(cfg *settings) *langResources {
+	return &langResources{
+		lang:     cfg.lang,
+		stopList: cfg.stopList,
+		stemmer:  cfg.stemmer,
+		pos:      cfg.pos,
+		posHint:  cfg.posHint,
+		modifier: cfg.modifier,
+		stems:    make(map[string]string),
+	}
+}
+
+// stem returns the stem of word, stemming each word only once.
+func (r *langResources) stem(word string) string {
+	if r.stemmer == nil {
+		return word
+	}
+	stem, ok := r.stems[word]
+	if !ok {
+		stem = r.stemmer.Stem(word)
+		r.stems[word] = stem
+	}
+	return stem
+}
+
+// sentenceLangs picks the resources for each sentence of a text, or each quote in it.
+type sentenceLangs struct {
+	base *langResources
+	// detect the language of each sentence, see WithMixedLang
+	mixed bool
+	langs map[string]*langResources
+}
+
+func newSentenceLangs(cfg *settings) *sentenceLangs {
+	base := newLangResources(cfg)
+	return &sentenceLangs{
+		base:  base,
+		mixed: cfg.mixed,
+		langs: map[string]*langResources{base.lang: base},
+	}
+}
+
+// spans splits sentence at its quote marks and returns the byte offsets
+// where each span ends with the resources for it.
+func (sl *sentenceLangs) spans(sentence string) ([]int, []*langResources) {
+	if !sl.mixed {
+		return []int{len(sentence)}, []*langResources{sl.base}
+	}
+	var ends []int
+	var res []*langResources
+	start := 0
+	for {
+		end := strings.IndexAny(sentence[start:], quoteMarks)
+		if end < 0 {
+			break
+		}
+		ends = append(ends, start+end)
+		res = append(res, sl.of(sentence[start:start+end]))
+		_, size := utf8.DecodeRuneInString(sentence[start+end:])
+		start += end + size
+	}
+	return append(ends, len(sentence)), append(res, sl.of(sentence[start:]))
+}
+
+// of returns the resources for text, those of the whole text unless it is
+// long enough to be detected as another language.
+func (sl *sentenceLangs) of(text string) *langResources {
+	if countLetters(text) < mixedMinLetters {
+		return sl.base
+	}
+	lang, confidence := detectLang(text, detectableLangs())
+	if lang == "" || confidence < mixedMinConfidence {
+		return sl.base
+	}
+	r := sl.langs[lang]
+	if r == nil {
+		// a language without modifiers of its own keeps those of the Tagger
+		cfg := settings{modifier: sl.base.modifier}
+		if err := cfg.setLang(lang); err != nil {
+			return sl.base
+		}
+		r = newLangResources(&cfg)
+		sl.langs[lang] = r
+	}
+	return r
+}
+
+func countLetters(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			n++
+		}
+	}
+	return n
+}
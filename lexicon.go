@@ -0,0 +1,223 @@
+package tek
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"embed"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// lexicon gives the POS types of a word.
+type lexicon interface {
+	posTypes(word string) ([]POSType, bool)
+}
+
+// vocabMap is a lexicon made of a POS dictionary given by the caller.
+type vocabMap map[string][]POSType
+
+// newVocabMap returns the types of the words of pos, or nil if pos is nil.
+// The types of the entries of a word are merged.
+func newVocabMap(pos []*Vocab) lexicon {
+	if pos == nil {
+		return nil
+	}
+	m := make(vocabMap, len(pos))
+	for _, vocab := range pos {
+		m[vocab.Word] = mergeTypes(m[vocab.Word], typesOf(vocab))
+	}
+	return m
+}
+
+func (m vocabMap) posTypes(word string) ([]POSType, bool) {
+	types, ok := m[word]
+	return types, ok
+}
+
+// typesOf returns the POS types of vocab, its Type alone if it has no Types.
+func typesOf(vocab *Vocab) []POSType {
+	if len(vocab.Types) > 0 {
+		return vocab.Types
+	}
+	return []POSType{{Type: vocab.Type}}
+}
+
+// mergeTypes returns the types of a and b, adding up the frequencies of a type found in both.
+func mergeTypes(a, b []POSType) []POSType {
+	res := append([]POSType(nil), a...)
+next:
+	for _, t := range b {
+		for i := range res {
+			if res[i].Type == t.Type {
+				res[i].Freq += t.Freq
+				continue next
+			}
+		}
+		res = append(res, t)
+	}
+	return res
+}
+
+// The lexicons of the built in languages, written by WriteLexicon.
+//
+//go:embed lexicon/*.tsv.gz
+var lexiconFiles embed.FS
+
+// WriteLexicon writes pos in the format of the lexicons embedded in the
+// lexicon/ directory, one gzipped line per word sorted by word, like
+// "rumah\tnomina" or "serang\tverba:3,nomina:1" for a word of several types.
+// The types of the entries of a word are merged, as in a POS dictionary.
+// Use cmd/tek-lexgen to build a lexicon from its sources.
+func WriteLexicon(w io.Writer, pos []*Vocab) error {
+	types := make(map[string][]POSType, len(pos))
+	for _, vocab := range pos {
+		if vocab.Word == "" || strings.ContainsAny(vocab.Word, "\t\n") {
+			return fmt.Errorf("tek: can't write the word %q to a lexicon", vocab.Word)
+		}
+		for _, t := range typesOf(vocab) {
+			if t.Type == "" || strings.ContainsAny(t.Type, "\t\n,:") || t.Freq < 0 {
+				return fmt.Errorf("tek: can't write %q as %q to a lexicon", vocab.Word, t.Type)
+			}
+		}
+		types[vocab.Word] = mergeTypes(types[vocab.Word], typesOf(vocab))
+	}
+	words := make([]string, 0, len(types))
+	for word := range types {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	gz, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(gz)
+	for _, word := range words {
+		bw.WriteString(word)
+		bw.WriteByte('\t')
+		for i, t := range types[word] {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			bw.WriteString(t.Type)
+			if len(types[word]) > 1 && t.Freq > 0 {
+				bw.WriteByte(':')
+				bw.WriteString(strconv.FormatFloat(t.Freq, 'g', -1, 64))
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// posTable is a sorted string table, the lines of a lexicon file kept in a
+// single string and searched in place.
+type posTable struct {
+	data string
+	// offset where each line starts
+	lines []uint32
+}
+
+// decodeLexicon reads a gzipped lexicon file.
+func decodeLexicon(gz []byte) (*posTable, error) {
+	r, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	t := &posTable{data: string(b), lines: make([]uint32, 0, bytes.Count(b, []byte{'\n'})+1)}
+	prev := ""
+	for start := 0; start < len(t.data); {
+		end := strings.IndexByte(t.data[start:], '\n')
+		if end < 0 {
+			end = len(t.data)
+		} else {
+			end += start
+		}
+		if start < end {
+			line := t.data[start:end]
+			tab := strings.IndexByte(line, '\t')
+			if tab <= 0 {
+				return nil, fmt.Errorf("tek: lexicon line %d: want a word and its type, got %q", len(t.lines)+1, line)
+			}
+			word := line[:tab]
+			if word <= prev {
+				return nil, fmt.Errorf("tek: lexicon line %d: %q is not sorted", len(t.lines)+1, word)
+			}
+			prev = word
+			t.lines = append(t.lines, uint32(start))
+		}
+		start = end + 1
+	}
+	return t, nil
+}
+
+// entry returns the word and the types of line i.
+func (t *posTable) entry(i int) (string, string) {
+	line := t.data[t.lines[i]:]
+	if end := strings.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+	tab := strings.IndexByte(line, '\t')
+	return line[:tab], line[tab+1:]
+}
+
+func (t *posTable) posTypes(word string) ([]POSType, bool) {
+	i := sort.Search(len(t.lines), func(i int) bool {
+		w, _ := t.entry(i)
+		return w >= word
+	})
+	if i == len(t.lines) {
+		return nil, false
+	}
+	w, field := t.entry(i)
+	if w != word {
+		return nil, false
+	}
+	var types []POSType
+	for _, part := range strings.Split(field, ",") {
+		typ, freq := part, 0.0
+		if colon := strings.IndexByte(part, ':'); colon >= 0 {
+			typ = part[:colon]
+			// written by WriteLexicon, a bad frequency only loses the weight of the type
+			freq, _ = strconv.ParseFloat(part[colon+1:], 64)
+		}
+		types = append(types, POSType{Type: typ, Freq: freq})
+	}
+	return types, true
+}
+
+var (
+	lexiconsMu sync.Mutex
+	lexicons   = make(map[string]*posTable)
+)
+
+// embeddedLexicon returns the lexicon of lang shipped in lexicon/, decoded
+// the first time it is needed, or nil if lang has none.
+func embeddedLexicon(lang string) *posTable {
+	lexiconsMu.Lock()
+	defer lexiconsMu.Unlock()
+	if t, ok := lexicons[lang]; ok {
+		return t
+	}
+	var t *posTable
+	if gz, err := lexiconFiles.ReadFile("lexicon/" + lang + ".tsv.gz"); err == nil {
+		if t, err = decodeLexicon(gz); err != nil {
+			panic(fmt.Sprintf("tek: broken lexicon of %q: %v", lang, err))
+		}
+	}
+	lexicons[lang] = t
+	return t
+}
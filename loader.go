@@ -0,0 +1,125 @@
+package tek
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LoadStopWords reads a stop word list with one entry per line, like "yang"
+// or "in fact". Entries are lowercased, blank lines and lines starting with
+// '#' are skipped. Give the result to WithStopWords or SetStopWords.
+func LoadStopWords(r io.Reader) ([]string, error) {
+	var res []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		res = append(res, strings.ToLower(strings.Join(strings.Fields(line), " ")))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// LoadPOSLexicon reads a POS dictionary in format f. FormatJSON is a list of
+// {"id", "word", "type"} objects like pos_id.json, with the "types" of a word
+// of several types. FormatTSV and FormatCSV are rows of a word, its type and
+// optionally how often the word is used that way, like "rumah\tnomina" or
+// "serang\tverba\t30", optionally under a "word" and "type" header. The rows
+// of a word are merged into one Vocab, numbered from 1 as their Id. Give the
+// result to WithPOS or SetPOS.
+func LoadPOSLexicon(r io.Reader, f Format) ([]*Vocab, error) {
+	switch f {
+	case FormatJSON:
+		var res []*Vocab
+		if err := json.NewDecoder(r).Decode(&res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	case FormatTSV:
+		return loadPOSRows(r, '\t')
+	case FormatCSV:
+		return loadPOSRows(r, ',')
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnsupportedFormat, f)
+}
+
+func loadPOSRows(r io.Reader, comma rune) ([]*Vocab, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	cr.TrimLeadingSpace = true
+	var res []*Vocab
+	types := make(map[string][]POSType)
+	for n := 1; ; n++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(row) < 2 {
+			return nil, fmt.Errorf("tek: row %d: want a word and its type, got %q", n, strings.Join(row, string(comma)))
+		}
+		word, typ := strings.ToLower(strings.TrimSpace(row[0])), strings.TrimSpace(row[1])
+		if n == 1 && word == "word" && typ == "type" {
+			continue
+		}
+		if word == "" || typ == "" {
+			return nil, fmt.Errorf("tek: row %d: empty word or type", n)
+		}
+		freq := 0.0
+		if len(row) > 2 && strings.TrimSpace(row[2]) != "" {
+			freq, err = strconv.ParseFloat(strings.TrimSpace(row[2]), 64)
+			if err != nil || freq < 0 {
+				return nil, fmt.Errorf("tek: row %d: bad frequency %q", n, row[2])
+			}
+		}
+		if _, ok := types[word]; !ok {
+			res = append(res, &Vocab{Id: len(res) + 1, Word: word})
+		}
+		types[word] = mergeTypes(types[word], []POSType{{Type: typ, Freq: freq}})
+	}
+	for _, vocab := range res {
+		vocab.Type = mostCommonType(types[vocab.Word])
+		if len(types[vocab.Word]) > 1 {
+			vocab.Types = types[vocab.Word]
+		}
+	}
+	return res, nil
+}
+
+// mostCommonType returns the type of types with the highest frequency, the first one on a tie.
+func mostCommonType(types []POSType) string {
+	best := 0
+	for i, t := range types {
+		if t.Freq > types[best].Freq {
+			best = i
+		}
+	}
+	return types[best].Type
+}
+
+// LoadModifiers reads the weight added for each POS type from a JSON object
+// like {"nama": 3.5, "nomina": 2.75}. Give the result to WithModifiers or SetModifiers.
+func LoadModifiers(r io.Reader) (map[string]float64, error) {
+	var res map[string]float64
+	if err := json.NewDecoder(r).Decode(&res); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		res = make(map[string]float64)
+	}
+	return res, nil
+}
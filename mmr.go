@@ -0,0 +1,116 @@
+package tek
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	// mmrPool is how many candidates per wanted tag are reordered by MMR.
+	mmrPool = 4
+	// mmrMinPrefix is the shortest word another word may start with to be alike, see sameWord.
+	mmrMinPrefix = 4
+)
+
+// diversify reorders the best candidates of termsInfo, sorted by Score, by
+// Maximal Marginal Relevance: each pick maximizes
+//
+//	lambda * relevance - (1 - lambda) * highest similarity to the tags picked before
+//
+// where relevance is Score scaled to [0, 1]. The candidates after the pool keep their order.
+func diversify(termsInfo []*Info, num int, doc *document, lambda float64) []*Info {
+	n := num * mmrPool
+	if n > len(termsInfo) {
+		n = len(termsInfo)
+	}
+	if n < 2 || termsInfo[0].Score <= 0 {
+		return termsInfo
+	}
+	pool := make([]*mmrCandidate, n)
+	for i, info := range termsInfo[:n] {
+		pool[i] = doc.newMMRCandidate(info)
+	}
+
+	maxScore := termsInfo[0].Score
+	result := make([]*Info, 0, len(termsInfo))
+	picked := make([]*mmrCandidate, 0, n)
+	for len(pool) > 0 {
+		best, bestValue := 0, math.Inf(-1)
+		for i, c := range pool {
+			sim := 0.0
+			for _, p := range picked {
+				sim = math.Max(sim, c.similarity(p))
+			}
+			if value := lambda*c.info.Score/maxScore - (1-lambda)*sim; value > bestValue {
+				best, bestValue = i, value
+			}
+		}
+		picked = append(picked, pool[best])
+		result = append(result, pool[best].info)
+		pool = append(pool[:best], pool[best+1:]...)
+	}
+	return append(result, termsInfo[n:]...)
+}
+
+// mmrCandidate is a tag with what similarity is measured on.
+type mmrCandidate struct {
+	info *Info
+	// stems of the words of the tag, the parts of hyphenated words included
+	words []string
+	// sentences the tag occurs in
+	sentences map[int]bool
+}
+
+func (doc *document) newMMRCandidate(info *Info) *mmrCandidate {
+	c := &mmrCandidate{info: info, sentences: make(map[int]bool)}
+	c.words = strings.FieldsFunc(info.Stem, func(r rune) bool { return r == ' ' || r == '-' })
+	for _, i := range doc.sentencesOf(info.Stem) {
+		c.sentences[i] = true
+	}
+	return c
+}
+
+// similarity is the higher of the share of their words, and the cosine of the sentences they occur in.
+func (c *mmrCandidate) similarity(o *mmrCandidate) float64 {
+	return math.Max(jaccard(c.words, o.words), cosine(c.sentences, o.sentences))
+}
+
+// jaccard returns the share of the words of a and b that are in both.
+func jaccard(a, b []string) float64 {
+	shared := 0
+	for _, x := range a {
+		for _, y := range b {
+			if sameWord(x, y) {
+				shared++
+				break
+			}
+		}
+	}
+	if union := len(a) + len(b) - shared; union > 0 {
+		return float64(shared) / float64(union)
+	}
+	return 0
+}
+
+// sameWord reports whether a and b are the same word, or one starts with the
+// other and both are at least mmrMinPrefix long, so "museum" and "museums" are
+// alike even without a stemmer.
+func sameWord(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return a == b || (len(a) >= mmrMinPrefix && strings.HasPrefix(b, a))
+}
+
+func cosine(a, b map[int]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	return float64(shared) / math.Sqrt(float64(len(a))*float64(len(b)))
+}
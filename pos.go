@@ -1,3 +1,160 @@
 package tek
 
-//go:generate go run gen.go
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//go:generate go run ./cmd/tek-lexgen -lang id -kbbi pos_id.json
+
+// POS types found in the Indonesian dictionary, used as keys of the modifiers.
+const (
+	// POSName is not in the dictionary, it is given to the words missing from it, mostly proper names.
+	POSName         = "nama"
+	POSNoun         = "nomina"
+	POSVerb         = "verba"
+	POSAdjective    = "adjektiva"
+	POSAdverb       = "adverbia"
+	POSNumeral      = "numeralia"
+	POSPronoun      = "pronomina"
+	POSPreposition  = "preposisi"
+	POSConjunction  = "konjungsi"
+	POSInterjection = "interjeksi"
+	POSOther        = "lain-lain"
+)
+
+// DefaultModifiers returns a copy of the weight added for each POS type by default.
+// A term's score is multiplied by 1 + modifier, types without a modifier are left as is.
+func DefaultModifiers() map[string]float64 {
+	return copyModifier(defaultModifier)
+}
+
+// posContext tells how the words of a text are written, to choose among the POS types of a word.
+type posContext struct {
+	// occurrences of each form of a stem in the text
+	forms func(stem string) map[string]int
+	// the POS type the affixes of form point to, see LanguagePack.POSHint
+	hint func(form, stem string) string
+}
+
+// posWeight returns the multiplier of term. A word with several POS types
+// gets the modifiers of the types its forms point to in ctx, and a blend of
+// its types weighted by their frequency where they don't tell. A phrase
+// missing from the dictionary gets the average multiplier of its words.
+func posWeight(term string, lex lexicon, modifier map[string]float64, ctx *posContext) float64 {
+	if types, ok := lex.posTypes(term); ok {
+		w := 1.0
+		for _, share := range typeShares(term, types, ctx) {
+			w += share.Freq * modifier[share.Type]
+		}
+		return w
+	}
+	words := strings.Fields(term)
+	if len(words) < 2 {
+		// not in the dictionary at all, most likely a name
+		return 1 + modifier[POSName]
+	}
+	sum := 0.0
+	for _, word := range words {
+		sum += posWeight(word, lex, modifier, ctx)
+	}
+	return sum / float64(len(words))
+}
+
+// typeShares returns the share of each of the types of word, adding up to 1,
+// largest first. Each occurrence counts for the type its form points to, the
+// others are split by the frequency of the types, or evenly without one.
+func typeShares(word string, types []POSType, ctx *posContext) []POSType {
+	if len(types) == 1 {
+		return []POSType{{Type: types[0].Type, Freq: 1}}
+	}
+	shares := make([]POSType, len(types))
+	total := 0.0
+	for i, t := range types {
+		shares[i].Type = t.Type
+		total += t.Freq
+	}
+	for i, t := range types {
+		if total > 0 {
+			shares[i].Freq = t.Freq / total
+		} else {
+			shares[i].Freq = 1 / float64(len(types))
+		}
+	}
+
+	if ctx != nil && ctx.hint != nil {
+		votes := make([]float64, len(types))
+		occurrences, hinted := 0.0, 0.0
+		for form, n := range ctx.forms(word) {
+			occurrences += float64(n)
+			hint := ctx.hint(form, word)
+			for i, t := range types {
+				if t.Type == hint {
+					votes[i] += float64(n)
+					hinted += float64(n)
+					break
+				}
+			}
+		}
+		if hinted > 0 {
+			for i := range shares {
+				shares[i].Freq = votes[i]/occurrences + (1-hinted/occurrences)*shares[i].Freq
+			}
+		}
+	}
+	sort.SliceStable(shares, func(i, j int) bool { return shares[i].Freq > shares[j].Freq })
+	return shares
+}
+
+// posTypes returns the POS type of term as posWeight sees it, with the share
+// of each for a word of several types, or the types of its words for a phrase missing from the dictionary.
+func posTypes(term string, lex lexicon, ctx *posContext) string {
+	if types, ok := lex.posTypes(term); ok {
+		shares := typeShares(term, types, ctx)
+		if len(shares) == 1 {
+			return shares[0].Type
+		}
+		parts := make([]string, len(shares))
+		for i, share := range shares {
+			parts[i] = fmt.Sprintf("%s %.0f%%", share.Type, 100*share.Freq)
+		}
+		return strings.Join(parts, "/")
+	}
+	words := strings.Fields(term)
+	if len(words) < 2 {
+		return POSName
+	}
+	for i, word := range words {
+		words[i] = posTypes(word, lex, ctx)
+	}
+	return strings.Join(words, " ")
+}
+
+// Prefixes that make a verb or a noun of an Indonesian root.
+var (
+	indonesianVerbPrefixes = []string{"me", "be", "di", "ter"}
+	indonesianNounPrefixes = []string{"pe", "ke"}
+)
+
+// indonesianPOSHint tells a verb like "menyerang" or "diserang" from a noun
+// like "serangan" or "penyerangan" by the affixes around stem.
+func indonesianPOSHint(form, stem string) string {
+	if form == stem {
+		return ""
+	}
+	for _, prefix := range indonesianVerbPrefixes {
+		if strings.HasPrefix(form, prefix) && !strings.HasPrefix(stem, prefix) {
+			return POSVerb
+		}
+	}
+	for _, prefix := range indonesianNounPrefixes {
+		if strings.HasPrefix(form, prefix) && !strings.HasPrefix(stem, prefix) {
+			return POSNoun
+		}
+	}
+	if strings.HasSuffix(form, "an") && !strings.HasSuffix(form, "kan") && !strings.HasSuffix(stem, "an") {
+		return POSNoun
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package tek
+
+import (
+	"context"
+	"strings"
+)
+
+// rakeSpan is a candidate keyword, the words [start, end) of a sentence.
+type rakeSpan struct {
+	sentence, start, end int
+}
+
+// rake scores keywords with Rapid Automatic Keyword Extraction. Candidates are
+// the runs of words between stop words and clause boundaries, each word is
+// scored by its degree, the total length of the candidates it occurs in,
+// divided by its frequency, and a candidate by the sum of its words.
+// Two candidates adjoining each other at least twice through the same stop
+// words are joined into one keyword, like "museum of narrative art".
+func rake(ctx context.Context, doc *document) ([]*Info, error) {
+	var spans []rakeSpan
+	for i, sen := range doc.sentences {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		start := 0
+		for j := 0; j <= len(sen); j++ {
+			stop := j == len(sen) || doc.stops[i][j]
+			if !stop && (j == start || !doc.breaks[i][j-1]) {
+				continue
+			}
+			if j > start {
+				spans = append(spans, rakeSpan{i, start, j})
+			}
+			start = j + 1
+			if !stop {
+				start = j
+			}
+		}
+	}
+
+	freq := make(map[string]float64)
+	degree := make(map[string]float64)
+	for _, span := range spans {
+		for _, stem := range doc.keys[span.sentence][span.start:span.end] {
+			freq[stem]++
+			degree[stem] += float64(span.end - span.start)
+		}
+	}
+	wordScore := func(span rakeSpan) float64 {
+		score := 0.0
+		for _, stem := range doc.keys[span.sentence][span.start:span.end] {
+			score += degree[stem] / freq[stem]
+		}
+		return score
+	}
+
+	keywords := newRakeKeywords(doc)
+	for k, span := range spans {
+		keywords.add(span, wordScore(span))
+		// join with the next candidate if only stop words are between them
+		if k+1 < len(spans) {
+			next := spans[k+1]
+			if next.sentence == span.sentence && !doc.crossesBreak(span.sentence, span.end-1, next.start) {
+				keywords.addAdjoined(rakeSpan{span.sentence, span.start, next.end}, wordScore(span)+wordScore(next))
+			}
+		}
+	}
+	return keywords.infos(), nil
+}
+
+// crossesBreak reports whether a clause boundary lies between words from and to of sentence i.
+func (doc *document) crossesBreak(i, from, to int) bool {
+	for j := from; j < to; j++ {
+		if doc.breaks[i][j] {
+			return true
+		}
+	}
+	return false
+}
+
+// rakeKeywords collects the distinct keywords, by first appearance.
+type rakeKeywords struct {
+	doc      *document
+	stems    []string
+	score    map[string]float64
+	counts   map[string]int
+	adjoined map[string]bool
+}
+
+func newRakeKeywords(doc *document) *rakeKeywords {
+	return &rakeKeywords{
+		doc:      doc,
+		score:    make(map[string]float64),
+		counts:   make(map[string]int),
+		adjoined: make(map[string]bool),
+	}
+}
+
+func (k *rakeKeywords) add(span rakeSpan, score float64) {
+	stem := strings.Join(k.doc.keys[span.sentence][span.start:span.end], " ")
+	if k.counts[stem] == 0 {
+		k.stems = append(k.stems, stem)
+		k.score[stem] = score
+	}
+	k.counts[stem]++
+}
+
+func (k *rakeKeywords) addAdjoined(span rakeSpan, score float64) {
+	stem := strings.Join(k.doc.keys[span.sentence][span.start:span.end], " ")
+	if k.counts[stem] == 0 {
+		k.adjoined[stem] = true
+	}
+	k.add(span, score)
+}
+
+// infos returns the keywords, leaving out the adjoined ones seen only once.
+func (k *rakeKeywords) infos() []*Info {
+	termsInfo := make([]*Info, 0, len(k.stems))
+	for _, stem := range k.stems {
+		count := k.counts[stem]
+		if k.adjoined[stem] && count < 2 {
+			continue
+		}
+		termsInfo = append(termsInfo, &Info{
+			Term:  k.doc.form(stem),
+			Stem:  stem,
+			Kind:  k.doc.kind(stem),
+			Tf:    float64(count) / float64(k.doc.words),
+			Score: k.score[stem],
+		})
+	}
+	return termsInfo
+}
@@ -0,0 +1,154 @@
+package tek
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// LanguagePack is what a Tagger needs to tag a language, see RegisterLanguage.
+type LanguagePack struct {
+	StopWords []string
+	// groups the forms of a word, nil leaves words as they are
+	Stemmer Stemmer
+	// POS dictionary, nil for the one built by tek-lexgen into lexicon/ if there is one
+	POS []*Vocab
+	// the POS type the affixes of a form of stem point to, or "" if they don't tell,
+	// used to choose among the types of a word that has several
+	POSHint func(form, stem string) string
+	// weight added for each POS type, nil keeps the modifiers of the Tagger, see DefaultModifiers
+	Modifiers map[string]float64
+	// abbreviations that don't end a sentence, lowercased and without their final dot, like "dr" or "a.n"
+	Abbreviations []string
+	// abbreviations that are also words, like "no" or "gen", they only don't end a
+	// sentence when capitalized and followed by a number or a capitalized word
+	WordAbbreviations []string
+	// abbreviations that often end a sentence, like "etc", they end it when followed by a capitalized word
+	FinalAbbreviations []string
+	// text written in the language, to detect it with SetLang("auto") and WithMixedLang.
+	// Malay, Javanese, Sundanese, German, French, Spanish and Dutch don't need one.
+	Sample string
+}
+
+// language is a registered LanguagePack with its lookup tables.
+type language struct {
+	code          string
+	pack          LanguagePack
+	stopList      *stopList
+	abbreviations map[string]abbreviation
+	// profile of Sample, nil to use the built in one
+	profile profile
+
+	lexOnce sync.Once
+	lex     lexicon
+}
+
+func newLanguage(code string, pack LanguagePack) *language {
+	l := &language{
+		code:          code,
+		pack:          pack,
+		stopList:      newStopList(pack.StopWords),
+		abbreviations: newAbbreviations(pack),
+	}
+	if pack.Modifiers != nil {
+		l.pack.Modifiers = copyModifier(pack.Modifiers)
+	}
+	if pack.Sample != "" {
+		l.profile = newProfile(pack.Sample)
+	}
+	return l
+}
+
+// lexicon returns the POS dictionary of the language, built the first time
+// the language is used and shared by every Tagger. A pack without POS uses
+// the lexicon embedded in lexicon/ for its code, if there is one.
+func (l *language) lexicon() lexicon {
+	l.lexOnce.Do(func() {
+		if l.pack.POS != nil {
+			l.lex = newVocabMap(l.pack.POS)
+		} else if t := embeddedLexicon(l.code); t != nil {
+			l.lex = t
+		}
+	})
+	return l.lex
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*language{
+		"en": newLanguage("en", LanguagePack{
+			StopWords:          englishStopWords,
+			Stemmer:            StemmerFunc(StemEN),
+			Abbreviations:      englishAbbreviations,
+			WordAbbreviations:  englishWordAbbreviations,
+			FinalAbbreviations: englishFinalAbbreviations,
+		}),
+		"id": newLanguage("id", LanguagePack{
+			StopWords:          indonesianStopWords,
+			Stemmer:            StemmerFunc(StemID),
+			POSHint:            indonesianPOSHint,
+			Abbreviations:      indonesianAbbreviations,
+			WordAbbreviations:  indonesianWordAbbreviations,
+			FinalAbbreviations: indonesianFinalAbbreviations,
+		}),
+	}
+)
+
+// RegisterLanguage makes the language code usable by SetLang, replacing any
+// language registered with the same code, "id" and "en" included. Taggers
+// already set to the language keep using the pack they were set with.
+// It returns an error wrapping ErrInvalidLang for an empty code or "auto".
+func RegisterLanguage(code string, pack LanguagePack) error {
+	if code == "" || code == "auto" {
+		return fmt.Errorf("%w: %q", ErrInvalidLang, code)
+	}
+	l := newLanguage(code, pack)
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[code] = l
+	return nil
+}
+
+// Languages returns the codes of the registered languages, sorted.
+func Languages() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	codes := make([]string, 0, len(registry))
+	for code := range registry {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// unregisterLanguage removes a language added by RegisterLanguage, for the tests to clean up after themselves.
+func unregisterLanguage(code string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(registry, code)
+}
+
+func lookupLanguage(code string) *language {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[code]
+}
+
+// detectableLangs returns the registered languages that have a profile to be detected by.
+func detectableLangs() []string {
+	var res []string
+	for _, code := range Languages() {
+		if profileOf(code) != nil {
+			res = append(res, code)
+		}
+	}
+	return res
+}
+
+// profileOf returns the profile of the Sample of a registered language, or the built in profile of code.
+func profileOf(code string) profile {
+	if l := lookupLanguage(code); l != nil && l.profile != nil {
+		return l.profile
+	}
+	return profiles()[code]
+}
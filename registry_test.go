@@ -0,0 +1,109 @@
+package tek_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/didasy/tek"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Language registry", func() {
+	tagalog := LanguagePack{
+		StopWords: []string{"ang", "ng", "mga", "sa", "na", "at", "ay", "si", "ni", "kay", "para", "ito", "iyon", "mula", "noong", "dahil", "kung", "hindi", "may", "rin", "din", "pa", "lamang", "ayon"},
+		// drop the "nag" of past verbs, enough to group "nagtanim" with "tanim"
+		Stemmer: StemmerFunc(func(word string) string {
+			if strings.HasPrefix(word, "nag") && len(word) > 5 {
+				return word[3:]
+			}
+			return word
+		}),
+		Abbreviations: []string{"gng", "bb"},
+		Sample: "Ang mga magsasaka sa lalawigan ay nagtanim ng palay noong nakaraang buwan dahil sa malakas na ulan. " +
+			"Ayon sa kagawaran ng agrikultura, ang ani ngayong taon ay mas mataas kaysa noong nakaraang taon. " +
+			"Sinabi ng alkalde na tutulungan ng pamahalaan ang mga magsasaka na maibenta ang kanilang palay sa tamang presyo. " +
+			"Marami sa mga bata ang hindi nakapasok sa paaralan dahil binaha ang mga daan papunta sa bayan. " +
+			"Ang pamilya ay naghanda ng pagkain para sa mga kapitbahay na nawalan ng tahanan dahil sa bagyo.",
+	}
+	text := "Ang mga magsasaka sa Nueva Ecija ay nagtanim ng palay ngayong linggo. Sinabi ni Gng. Santos na ang palay ay mas mura ngayon. " +
+		"Ayon sa mga magsasaka, ang tanim na palay ay hindi nasira ng bagyo. Ang presyo ng palay ay tataas dahil sa kakulangan ng bigas sa bayan."
+
+	BeforeEach(func() {
+		Expect(RegisterLanguage("tl", tagalog)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		UnregisterLanguage("tl")
+		UnregisterLanguage("tl-pos")
+	})
+
+	It("Should list the registered languages", func() {
+		Expect(Languages()).To(ContainElement("tl"))
+		Expect(Languages()).To(ContainElement("en"))
+		Expect(Languages()).To(ContainElement("id"))
+	})
+
+	It("Should not register a language without a code or as auto", func() {
+		Expect(errors.Is(RegisterLanguage("", tagalog), ErrInvalidLang)).To(BeTrue())
+		Expect(errors.Is(RegisterLanguage("auto", tagalog), ErrInvalidLang)).To(BeTrue())
+	})
+
+	It("Should tag with the stop words and stemmer of the pack", func() {
+		tagger, err := New(WithLang("tl"))
+		Expect(err).To(BeNil())
+		Expect(tagger.Lang()).To(Equal("tl"))
+		tags := tagger.GetTags(text, 100)
+		Expect(terms(tags[:5])).To(ContainElement("palay"))
+		var tanim *Info
+		for _, tag := range tags {
+			Expect(tagalog.StopWords).NotTo(ContainElement(tag.Term))
+			if tag.Stem == "tanim" {
+				tanim = tag
+			}
+		}
+		// "nagtanim" and "tanim" are one tag
+		Expect(tanim).NotTo(BeNil())
+		Expect(terms(tags)).NotTo(ContainElement("tanim"))
+	})
+
+	It("Should use the abbreviations of the pack to split sentences", func() {
+		Expect(SplitSentences(text, "tl")).To(HaveLen(4))
+		Expect(SplitSentences(text, "en")).To(HaveLen(5))
+	})
+
+	It("Should detect the language from its sample", func() {
+		lang, _ := DetectLang(text)
+		Expect(lang).To(Equal("tl"))
+
+		tagger, _ := New(WithLang("auto"))
+		res, err := tagger.Analyze(context.Background(), text, 3)
+		Expect(err).To(BeNil())
+		Expect(res.Lang).To(Equal("tl"))
+	})
+
+	It("Should replace the modifiers of the Tagger only if the pack has some", func() {
+		pack := tagalog
+		pack.POS = []*Vocab{{Word: "palay", Type: POSNoun}}
+		pack.Modifiers = map[string]float64{POSNoun: 10}
+		Expect(RegisterLanguage("tl-pos", pack)).To(Succeed())
+
+		plain, _ := New(WithLang("tl"))
+		boosted, _ := New(WithLang("tl-pos"))
+		Expect(boosted.GetTags(text, 1)[0].Term).To(Equal("palay"))
+		var before, after float64
+		for _, tag := range plain.GetTags(text, 20) {
+			if tag.Term == "palay" {
+				before = tag.Score
+			}
+		}
+		for _, tag := range boosted.GetTags(text, 20) {
+			if tag.Term == "palay" {
+				after = tag.Score
+			}
+		}
+		Expect(after).To(BeNumerically("~", before*11, 1e-9))
+	})
+})
@@ -0,0 +1,268 @@
+package tek
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Abbreviations that don't end a sentence, lowercased and without their final dot.
+var englishAbbreviations = []string{
+	"mr", "mrs", "ms", "dr", "prof", "sr", "jr", "st", "mt", "ft", "vs", "e.g", "i.e", "cf", "al",
+	"inc", "ltd", "corp", "bros", "dept", "univ", "assn", "approx", "nos", "vol", "figs", "p", "pp",
+	"gov", "sen", "rep", "lt", "col", "capt", "sgt", "cmdr", "adm", "rev", "hon", "pres",
+	"jan", "feb", "apr", "jun", "jul", "aug", "sep", "sept", "oct", "nov", "dec",
+	"mon", "tue", "thu", "fri",
+	"u.n", "ph.d", "b.a", "m.a", "b.sc", "m.sc",
+}
+
+// Abbreviations that are also words, they only don't end a sentence when
+// capitalized and followed by a number or a capitalized word, like "No. 5" or "Gen. Smith".
+var englishWordAbbreviations = []string{"no", "gen", "co", "fig", "est", "mar", "wed", "sat", "sun"}
+
+// Abbreviations that often end a sentence, they end it when followed by a
+// capitalized word, like "apples, pears, etc. Then".
+var englishFinalAbbreviations = []string{"etc", "u.s", "u.k", "a.m", "p.m"}
+
+var indonesianAbbreviations = []string{
+	"dr", "drg", "prof", "ir", "drs", "dra", "h", "hj", "kh", "tn", "ny", "nn", "sdr", "sdri", "bpk", "yth", "ybs",
+	"jl", "jln", "gg", "no", "rt", "rw", "kel", "kec", "kab", "prov", "tlp", "telp", "hlm", "tgl", "thn",
+	"a.n", "u.p", "u.b", "sda", "spt", "krn", "yg", "dgn", "utk",
+	"pt", "cv", "tbk", "s.h", "s.e", "s.t", "s.pd", "s.kom", "s.sos", "s.ip", "m.m", "m.si", "m.sc", "m.hum", "ph.d",
+	"jend", "letjen", "mayjen", "brigjen", "letkol", "kapt", "lettu", "letda", "serka", "kompol", "akbp", "kombes", "irjen", "komjen", "bripka",
+}
+
+var indonesianWordAbbreviations = []string{"kol", "pol"}
+
+var indonesianFinalAbbreviations = []string{"dll", "dsb", "dst", "tsb"}
+
+// abbreviation tells when an abbreviation followed by a dot ends a sentence.
+type abbreviation int
+
+const (
+	// never ends a sentence, see LanguagePack.Abbreviations
+	abbrAlways abbreviation = iota + 1
+	// also a word, see LanguagePack.WordAbbreviations
+	abbrWord
+	// often ends a sentence, see LanguagePack.FinalAbbreviations
+	abbrFinal
+)
+
+// Runes that end a sentence, the closing quotes and brackets that may follow them
+// and the opening ones that may start the next sentence.
+const (
+	sentenceEnders  = ".!?…"
+	sentenceClosers = ")]}\"'”’»"
+	sentenceOpeners = "([{\"'“‘«"
+)
+
+// Span is the byte range [Start, End) of a piece of text.
+type Span struct {
+	Start, End int
+}
+
+// SplitSentences splits text into sentences, using the abbreviations of lang
+// (any registered language, see LanguagePack) to tell a final dot from the dot
+// of "Dr." or "Jl.". A dot inside a token, like in "1.600" or "go1.21", the dot
+// of an initial like "Harun P." or "J. R. Tolkien" and an ending followed by a
+// lowercase word never end a sentence. Closing quotes and brackets stay with their sentence,
+// and a blank line always ends one.
+func SplitSentences(text string, lang string) []string {
+	var abbreviations map[string]abbreviation
+	if l := lookupLanguage(lang); l != nil {
+		abbreviations = l.abbreviations
+	}
+	spans := splitSentences(text, abbreviations)
+	res := make([]string, len(spans))
+	for i, sp := range spans {
+		res[i] = text[sp.Start:sp.End]
+	}
+	return res
+}
+
+// newAbbreviations maps the abbreviations of a LanguagePack to their kind.
+func newAbbreviations(pack LanguagePack) map[string]abbreviation {
+	res := make(map[string]abbreviation, len(pack.Abbreviations)+len(pack.WordAbbreviations)+len(pack.FinalAbbreviations))
+	for _, abbr := range pack.Abbreviations {
+		res[abbr] = abbrAlways
+	}
+	for _, abbr := range pack.WordAbbreviations {
+		res[abbr] = abbrWord
+	}
+	for _, abbr := range pack.FinalAbbreviations {
+		res[abbr] = abbrFinal
+	}
+	return res
+}
+
+// splitSentences returns the spans of the sentences of text, trimmed of spaces.
+func splitSentences(text string, abbreviations map[string]abbreviation) []Span {
+	var spans []Span
+	emit := func(start, end int) {
+		for start < end {
+			r, size := utf8.DecodeRuneInString(text[start:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			start += size
+		}
+		for end > start {
+			r, size := utf8.DecodeLastRuneInString(text[:end])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			end -= size
+		}
+		if start < end {
+			spans = append(spans, Span{start, end})
+		}
+	}
+
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r == '\n' {
+			if next := blankLineEnd(text, i+size); next > 0 {
+				emit(start, i)
+				start, i = next, next
+				continue
+			}
+		}
+		if !strings.ContainsRune(sentenceEnders, r) {
+			i += size
+			continue
+		}
+
+		// take the whole run like "?!" or "...", then the closing quotes and brackets
+		endersEnd := skipRunes(text, i, sentenceEnders)
+		end := skipRunes(text, endersEnd, sentenceClosers)
+		if next, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && !unicode.IsSpace(next) {
+			// inside a token, like 1.600, example.com or go1.21
+			i = end
+			continue
+		}
+		if isSentenceEnd(text, start, i, endersEnd, end, abbreviations) {
+			emit(start, end)
+			start = end
+		}
+		i = end
+	}
+	emit(start, len(text))
+	return spans
+}
+
+// blankLineEnd returns where the blank line following the newline ending at i ends, or 0 if there is none.
+func blankLineEnd(text string, i int) int {
+	for j := i; j < len(text); {
+		r, size := utf8.DecodeRuneInString(text[j:])
+		if r == '\n' {
+			return j + size
+		}
+		if !unicode.IsSpace(r) {
+			return 0
+		}
+		j += size
+	}
+	return 0
+}
+
+// skipRunes returns the index of the first rune from i that isn't in chars.
+func skipRunes(text string, i int, chars string) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !strings.ContainsRune(chars, r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// isSentenceEnd reports whether the enders text[i:endersEnd], followed by
+// closers up to end, end the sentence starting at start.
+func isSentenceEnd(text string, start, i, endersEnd, end int, abbreviations map[string]abbreviation) bool {
+	// a sentence doesn't start with a lowercase letter
+	next := strings.TrimLeftFunc(text[end:], unicode.IsSpace)
+	next = strings.TrimLeft(next, sentenceOpeners)
+	r, _ := utf8.DecodeRuneInString(next)
+	if text[i:endersEnd] == "." {
+		// the word the dot belongs to, without its opening quotes and brackets
+		wordStart := strings.LastIndexFunc(text[:i], unicode.IsSpace) + 1
+		word := strings.TrimLeft(text[wordStart:i], sentenceOpeners)
+		switch abbreviations[strings.ToLower(word)] {
+		case abbrAlways:
+			return false
+		case abbrWord:
+			if startsUpper(word) && (unicode.IsDigit(r) || unicode.IsUpper(r)) {
+				return false
+			}
+		case abbrFinal:
+			return !unicode.IsLower(r)
+		}
+		if isInitials(word) || isInitial(word, text[start:wordStart], next) {
+			return false
+		}
+	}
+	return !unicode.IsLower(r)
+}
+
+// isInitials reports whether word is letters separated by dots like "a.n" or "U.S".
+func isInitials(word string) bool {
+	if !strings.Contains(word, ".") {
+		return false
+	}
+	for _, part := range strings.Split(word, ".") {
+		if utf8.RuneCountInString(part) != 1 {
+			return false
+		}
+		if r, _ := utf8.DecodeRuneInString(part); !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isInitial reports whether word, followed by a dot, is the initial of a name:
+// a capital letter after a capitalized word that doesn't start the sentence,
+// like "Harun P.", or next to another initial, like "J. R. Tolkien".
+// before is the sentence up to word, next the text after the dot.
+func isInitial(word, before, next string) bool {
+	if !isCapitalLetter(word) {
+		return false
+	}
+	prev := lastWord(before)
+	if isCapitalLetter(strings.TrimSuffix(prev, ".")) && strings.HasSuffix(prev, ".") {
+		return true
+	}
+	if first := firstWord(next); strings.HasSuffix(first, ".") && isCapitalLetter(strings.TrimSuffix(first, ".")) {
+		return true
+	}
+	return prev != "" && startsUpper(prev) && strings.IndexFunc(prev, func(r rune) bool { return !unicode.IsLetter(r) }) < 0
+}
+
+// isCapitalLetter reports whether s is a single uppercase letter.
+func isCapitalLetter(s string) bool {
+	r, size := utf8.DecodeRuneInString(s)
+	return size > 0 && size == len(s) && unicode.IsUpper(r)
+}
+
+// lastWord returns the last word of the sentence so far, or "" if it has a single word,
+// as a capital at the start of a sentence says nothing of the word.
+// It only reads the sentence back to the space before that word.
+func lastWord(sentence string) string {
+	sentence = strings.TrimSpace(sentence)
+	i := strings.LastIndexFunc(sentence, unicode.IsSpace)
+	if i < 0 {
+		return ""
+	}
+	_, size := utf8.DecodeRuneInString(sentence[i:])
+	return sentence[i+size:]
+}
+
+// firstWord returns the text up to its first space, without reading further.
+func firstWord(text string) string {
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		return text[:i]
+	}
+	return text
+}
@@ -0,0 +1,113 @@
+package tek_test
+
+import (
+	. "github.com/didasy/tek"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sentence splitter", func() {
+
+	Describe("Splitting Indonesian text", func() {
+		It("Should not split on initials, numbers or dates", func() {
+			text := "Pria itu diidentifikasi sebagai Harun P. Menurut Titz, Harun terancam hukuman penjara. " +
+				"Serangan itu melibatkan 1.600 jihadis. Reuters, Rabu (21/1/2015), menuturkan hal itu."
+			Expect(SplitSentences(text, "id")).To(Equal([]string{
+				"Pria itu diidentifikasi sebagai Harun P. Menurut Titz, Harun terancam hukuman penjara.",
+				"Serangan itu melibatkan 1.600 jihadis.",
+				"Reuters, Rabu (21/1/2015), menuturkan hal itu.",
+			}))
+		})
+
+		It("Should not split on abbreviations", func() {
+			text := "Surat a.n. Budi dikirim ke Jl. Sudirman No. 5 oleh Prof. Sutomo. Dr. Amir menerimanya."
+			Expect(SplitSentences(text, "id")).To(Equal([]string{
+				"Surat a.n. Budi dikirim ke Jl. Sudirman No. 5 oleh Prof. Sutomo.",
+				"Dr. Amir menerimanya.",
+			}))
+		})
+
+		It("Should split after words that are not abbreviations", func() {
+			Expect(SplitSentences("Dia tinggal di kota. Mereka pulang ke rumah.", "id")).To(Equal([]string{
+				"Dia tinggal di kota.",
+				"Mereka pulang ke rumah.",
+			}))
+			Expect(SplitSentences("Ia menanam kol. Pol itu dipakai lagi. Kol. Pol. Amir datang.", "id")).To(HaveLen(3))
+		})
+
+		It("Should end a sentence at an abbreviation followed by a capital", func() {
+			Expect(SplitSentences("Kami membeli buah, sayur, dll. Kemudian kami pulang.", "id")).To(Equal([]string{
+				"Kami membeli buah, sayur, dll.",
+				"Kemudian kami pulang.",
+			}))
+			Expect(SplitSentences("Kami membeli buah, sayur, dsb. dari pasar itu.", "id")).To(HaveLen(1))
+		})
+	})
+
+	Describe("Splitting English text", func() {
+		It("Should keep closing quotes and handle ellipses", func() {
+			text := "Mr. Lucas said \"It is done.\" The museum costs $1.5 billion... or more. Wait... What? Yes!"
+			Expect(SplitSentences(text, "en")).To(Equal([]string{
+				"Mr. Lucas said \"It is done.\"",
+				"The museum costs $1.5 billion... or more.",
+				"Wait...",
+				"What?",
+				"Yes!",
+			}))
+		})
+
+		It("Should not split inside tokens", func() {
+			Expect(SplitSentences("Install go1.21 from golang.org today. Then build it.", "en")).To(Equal([]string{
+				"Install go1.21 from golang.org today.",
+				"Then build it.",
+			}))
+		})
+
+		It("Should end a sentence at a blank line", func() {
+			Expect(SplitSentences("A Title Without A Dot\n\nThe text starts here.", "en")).To(Equal([]string{
+				"A Title Without A Dot",
+				"The text starts here.",
+			}))
+		})
+
+		It("Should split after words that are only sometimes abbreviations", func() {
+			Expect(SplitSentences("The answer was no. We left early.", "en")).To(Equal([]string{
+				"The answer was no.",
+				"We left early.",
+			}))
+			Expect(SplitSentences("I saw the sun. It was hot.", "en")).To(HaveLen(2))
+			Expect(SplitSentences("See Fig. 3 and No. 5 of the list. Gen. Smith agreed.", "en")).To(Equal([]string{
+				"See Fig. 3 and No. 5 of the list.",
+				"Gen. Smith agreed.",
+			}))
+		})
+
+		It("Should end a sentence at an abbreviation followed by a capital", func() {
+			Expect(SplitSentences("We bought apples, pears, etc. Then we left. He moved to the U.S. It was cold.", "en")).To(Equal([]string{
+				"We bought apples, pears, etc.",
+				"Then we left.",
+				"He moved to the U.S.",
+				"It was cold.",
+			}))
+			Expect(SplitSentences("We bought apples, pears, etc. at the market.", "en")).To(HaveLen(1))
+		})
+
+		It("Should only take a capital letter after a name or another initial as an initial", func() {
+			Expect(SplitSentences("We chose plan B. Then we left.", "en")).To(Equal([]string{
+				"We chose plan B.",
+				"Then we left.",
+			}))
+			Expect(SplitSentences("A. Then came the rest. It was read by J. R. Tolkien. Books by George R. R. Martin sold well.", "en")).To(Equal([]string{
+				"A.",
+				"Then came the rest.",
+				"It was read by J. R. Tolkien.",
+				"Books by George R. R. Martin sold well.",
+			}))
+		})
+
+		It("Should use no abbreviations for other languages", func() {
+			Expect(SplitSentences("Es kam Dr. Müller.", "de")).To(HaveLen(2))
+		})
+	})
+})
@@ -0,0 +1,15 @@
+package tek
+
+// Stemmer reduces a word to the root used to group it with its other forms,
+// e.g. "penyerangan" and "menyerang" are both counted as "serang".
+type Stemmer interface {
+	Stem(word string) string
+}
+
+// StemmerFunc adapts an ordinary function to a Stemmer.
+type StemmerFunc func(word string) string
+
+// Stem calls f(word).
+func (f StemmerFunc) Stem(word string) string {
+	return f(word)
+}
@@ -0,0 +1,290 @@
+package tek
+
+import "strings"
+
+// Words with an irregular stem, looked up before anything else.
+var englishStemExceptions = map[string]string{
+	"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie", "tying": "tie",
+	"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli", "only": "onli", "singly": "singl",
+	// invariant forms
+	"sky": "sky", "news": "news", "howe": "howe", "atlas": "atlas", "cosmos": "cosmos", "bias": "bias", "andes": "andes",
+}
+
+// Words left alone once step 1a is done.
+var englishStep1aExceptions = map[string]bool{
+	"inning": true, "outing": true, "canning": true, "herring": true, "earring": true,
+	"proceed": true, "exceed": true, "succeed": true,
+}
+
+// Suffixes of each step, a step only looks at the longest suffix the word ends with.
+var (
+	englishStep2Suffixes = [][2]string{
+		{"ization", "ize"}, {"ational", "ate"}, {"fulness", "ful"}, {"ousness", "ous"}, {"iveness", "ive"},
+		{"tional", "tion"}, {"biliti", "ble"}, {"lessli", "less"},
+		{"entli", "ent"}, {"ation", "ate"}, {"alism", "al"}, {"aliti", "al"}, {"ousli", "ous"}, {"iviti", "ive"}, {"fulli", "ful"},
+		{"enci", "ence"}, {"anci", "ance"}, {"abli", "able"}, {"izer", "ize"}, {"ator", "ate"}, {"alli", "al"},
+		{"bli", "ble"}, {"ogi", "og"}, {"li", ""},
+	}
+	englishStep3Suffixes = [][2]string{
+		{"ational", "ate"}, {"tional", "tion"}, {"alize", "al"}, {"icate", "ic"}, {"iciti", "ic"}, {"ative", ""},
+		{"ical", "ic"}, {"ness", ""}, {"ful", ""},
+	}
+	englishStep4Suffixes = []string{
+		"ement", "ance", "ence", "able", "ible", "ment", "ant", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+		"al", "er", "ic",
+	}
+)
+
+// StemEN returns the stem of an English word using the Porter2 (Snowball English)
+// algorithm, e.g. "designs", "designed" and "designing" all become "design".
+// Words with other than ASCII letters, hyphens and apostrophes are returned unchanged.
+func StemEN(word string) string {
+	word = strings.ToLower(word)
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if c := word[i]; (c < 'a' || c > 'z') && c != '\'' && c != '-' {
+			return word
+		}
+	}
+	if stem, ok := englishStemExceptions[word]; ok {
+		return stem
+	}
+
+	s := &porter2{w: []byte(strings.TrimPrefix(word, "'"))}
+	s.markY()
+	s.findRegions()
+	s.step0()
+	s.step1a()
+	if englishStep1aExceptions[string(s.w)] {
+		return string(s.w)
+	}
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	return strings.Replace(string(s.w), "Y", "y", -1)
+}
+
+// porter2 is a word being stemmed, with the start of its R1 and R2 regions.
+// The regions are found once, a region starting past the end of the word is empty.
+type porter2 struct {
+	w      []byte
+	r1, r2 int
+}
+
+func isEnglishVowel(c byte) bool {
+	return c == 'a' || c == 'e' || c == 'i' || c == 'o' || c == 'u' || c == 'y'
+}
+
+// markY turns a y starting the word or following a vowel into Y, so it counts as a consonant.
+func (s *porter2) markY() {
+	for i, c := range s.w {
+		if c == 'y' && (i == 0 || isEnglishVowel(s.w[i-1])) {
+			s.w[i] = 'Y'
+		}
+	}
+}
+
+// findRegions sets R1 after the first non-vowel following a vowel, and R2 the same way inside R1.
+func (s *porter2) findRegions() {
+	s.r1 = len(s.w)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(s.w), prefix) {
+			s.r1 = len(prefix)
+			break
+		}
+	}
+	if s.r1 == len(s.w) {
+		s.r1 = s.regionAfter(0)
+	}
+	s.r2 = s.regionAfter(s.r1)
+}
+
+func (s *porter2) regionAfter(start int) int {
+	for i := start + 1; i < len(s.w); i++ {
+		if !isEnglishVowel(s.w[i]) && isEnglishVowel(s.w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(s.w)
+}
+
+func (s *porter2) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(s.w), suffix)
+}
+
+// inR1 and inR2 report whether suffix, which the word ends with, lies in the region.
+func (s *porter2) inR1(suffix string) bool {
+	return len(s.w)-len(suffix) >= s.r1
+}
+
+func (s *porter2) inR2(suffix string) bool {
+	return len(s.w)-len(suffix) >= s.r2
+}
+
+func (s *porter2) replace(suffix, with string) {
+	s.w = append(s.w[:len(s.w)-len(suffix)], with...)
+}
+
+// longest returns the longest of suffixes the word ends with.
+func (s *porter2) longest(suffixes ...string) string {
+	found := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(found) && s.hasSuffix(suffix) {
+			found = suffix
+		}
+	}
+	return found
+}
+
+func (s *porter2) containsVowel(w []byte) bool {
+	for _, c := range w {
+		if isEnglishVowel(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsShortSyllable reports whether w ends with a vowel followed by a non-vowel other than w, x or Y
+// and preceded by a non-vowel, or is a vowel at the beginning of the word followed by a non-vowel.
+func endsShortSyllable(w []byte) bool {
+	n := len(w)
+	if n == 2 {
+		return isEnglishVowel(w[0]) && !isEnglishVowel(w[1])
+	}
+	return n > 2 && !isEnglishVowel(w[n-3]) && isEnglishVowel(w[n-2]) &&
+		!isEnglishVowel(w[n-1]) && w[n-1] != 'w' && w[n-1] != 'x' && w[n-1] != 'Y'
+}
+
+func (s *porter2) isShort() bool {
+	return s.r1 >= len(s.w) && endsShortSyllable(s.w)
+}
+
+func (s *porter2) step0() {
+	if suffix := s.longest("'", "'s", "'s'"); suffix != "" {
+		s.replace(suffix, "")
+	}
+}
+
+func (s *porter2) step1a() {
+	switch suffix := s.longest("sses", "ied", "ies", "s", "us", "ss"); suffix {
+	case "sses":
+		s.replace(suffix, "ss")
+	case "ied", "ies":
+		if len(s.w) > 4 {
+			s.replace(suffix, "i")
+		} else {
+			s.replace(suffix, "ie")
+		}
+	case "s":
+		// delete if a vowel comes before the letter preceding the s
+		if len(s.w) > 2 && s.containsVowel(s.w[:len(s.w)-2]) {
+			s.replace(suffix, "")
+		}
+	}
+}
+
+func (s *porter2) step1b() {
+	switch suffix := s.longest("eed", "eedly", "ed", "edly", "ing", "ingly"); suffix {
+	case "eed", "eedly":
+		if s.inR1(suffix) {
+			s.replace(suffix, "ee")
+		}
+	case "ed", "edly", "ing", "ingly":
+		if !s.containsVowel(s.w[:len(s.w)-len(suffix)]) {
+			return
+		}
+		s.replace(suffix, "")
+		switch {
+		case s.hasSuffix("at"), s.hasSuffix("bl"), s.hasSuffix("iz"):
+			s.w = append(s.w, 'e')
+		case s.longest("bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt") != "":
+			s.w = s.w[:len(s.w)-1]
+		case s.isShort():
+			s.w = append(s.w, 'e')
+		}
+	}
+}
+
+func (s *porter2) step1c() {
+	n := len(s.w)
+	if n > 2 && (s.w[n-1] == 'y' || s.w[n-1] == 'Y') && !isEnglishVowel(s.w[n-2]) {
+		s.w[n-1] = 'i'
+	}
+}
+
+func (s *porter2) step2() {
+	for _, rule := range englishStep2Suffixes {
+		suffix := rule[0]
+		if !s.hasSuffix(suffix) {
+			continue
+		}
+		if !s.inR1(suffix) {
+			return
+		}
+		n := len(s.w) - len(suffix)
+		switch suffix {
+		case "ogi":
+			if n > 0 && s.w[n-1] == 'l' {
+				s.replace(suffix, rule[1])
+			}
+		case "li":
+			if n > 0 && strings.IndexByte("cdeghkmnrt", s.w[n-1]) >= 0 {
+				s.replace(suffix, rule[1])
+			}
+		default:
+			s.replace(suffix, rule[1])
+		}
+		return
+	}
+}
+
+func (s *porter2) step3() {
+	for _, rule := range englishStep3Suffixes {
+		suffix := rule[0]
+		if !s.hasSuffix(suffix) {
+			continue
+		}
+		if s.inR1(suffix) && (suffix != "ative" || s.inR2(suffix)) {
+			s.replace(suffix, rule[1])
+		}
+		return
+	}
+}
+
+func (s *porter2) step4() {
+	for _, suffix := range englishStep4Suffixes {
+		if !s.hasSuffix(suffix) {
+			continue
+		}
+		if !s.inR2(suffix) {
+			return
+		}
+		if suffix == "ion" {
+			if n := len(s.w) - 3; n > 0 && (s.w[n-1] == 's' || s.w[n-1] == 't') {
+				s.replace(suffix, "")
+			}
+			return
+		}
+		s.replace(suffix, "")
+		return
+	}
+}
+
+func (s *porter2) step5() {
+	switch {
+	case s.hasSuffix("e"):
+		if s.inR2("e") || (s.inR1("e") && !endsShortSyllable(s.w[:len(s.w)-1])) {
+			s.replace("e", "")
+		}
+	case s.hasSuffix("l"):
+		if s.inR2("l") && s.hasSuffix("ll") {
+			s.replace("l", "")
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package tek
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	indonesianRootsOnce sync.Once
+	indonesianRoots     *posTable
+)
+
+// isIndonesianRoot reports whether word is in the bundled KBBI word list.
+func isIndonesianRoot(word string) bool {
+	indonesianRootsOnce.Do(func() {
+		indonesianRoots = embeddedLexicon("id")
+	})
+	_, ok := indonesianRoots.posTypes(word)
+	return ok
+}
+
+// Suffixes in the order they are removed, a word takes at most one of each group.
+var indonesianSuffixes = [][]string{
+	// inflectional particles
+	{"lah", "kah", "tah", "pun"},
+	// possessive pronouns
+	{"ku", "mu", "nya"},
+	// derivational suffixes, longest first
+	{"isasi", "isme", "kan", "an", "is", "i"},
+}
+
+// StemID returns the root of an Indonesian word, following the Nazief-Adriani
+// algorithm with the confix stripping rules of Sastrawi. Inflectional,
+// possessive and derivational suffixes are removed first, then up to three
+// prefixes, and each candidate root is checked against the KBBI word list.
+// Words whose root can't be found are returned unchanged.
+func StemID(word string) string {
+	word = strings.ToLower(word)
+	if isIndonesianRoot(word) {
+		return word
+	}
+	if strings.Contains(word, "-") {
+		return stemIDPlural(word)
+	}
+	if root, ok := stemIDSingular(word); ok {
+		return root
+	}
+	return word
+}
+
+// stemIDPlural stems reduplicated words like "negara-negara" or "bermain-main".
+// Other hyphenated words, like "al-assad", are returned unchanged.
+func stemIDPlural(word string) string {
+	parts := strings.SplitN(word, "-", 2)
+	if parts[0] == "" || parts[1] == "" {
+		return word
+	}
+	root1 := StemID(parts[0])
+	root2 := StemID(parts[1])
+	if root1 == root2 {
+		return root1
+	}
+	return word
+}
+
+func stemIDSingular(word string) (string, bool) {
+	// confixes like be-an or di-i lose their prefix first
+	if isIndonesianConfixPrecedence(word) {
+		if root, ok := removeIDPrefixes(word, 0, "", findIDRootBySuffix); ok {
+			return root, true
+		}
+	}
+
+	forms := indonesianSuffixForms(word)
+	for _, form := range forms[1:] {
+		if isIndonesianRoot(form) {
+			return form, true
+		}
+	}
+	// remove the prefixes of the shortest form first, then give the suffixes back one by one
+	for i := len(forms) - 1; i >= 0; i-- {
+		if root, ok := removeIDPrefixes(forms[i], 0, "", findIDRoot); ok {
+			return root, true
+		}
+	}
+	return "", false
+}
+
+func isIndonesianConfixPrecedence(word string) bool {
+	switch {
+	case strings.HasPrefix(word, "be"):
+		return strings.HasSuffix(word, "lah") || strings.HasSuffix(word, "an")
+	case strings.HasPrefix(word, "me"), strings.HasPrefix(word, "di"), strings.HasPrefix(word, "pe"), strings.HasPrefix(word, "ter"):
+		return strings.HasSuffix(word, "i")
+	}
+	return false
+}
+
+// indonesianSuffixForms returns word followed by what is left after removing each suffix in turn.
+func indonesianSuffixForms(word string) []string {
+	forms := []string{word}
+	for _, group := range indonesianSuffixes {
+		for _, suffix := range group {
+			// keep at least a two letters root
+			if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 2 {
+				word = word[:len(word)-len(suffix)]
+				forms = append(forms, word)
+				break
+			}
+		}
+	}
+	return forms
+}
+
+func findIDRoot(word string) (string, bool) {
+	return word, isIndonesianRoot(word)
+}
+
+func findIDRootBySuffix(word string) (string, bool) {
+	for _, form := range indonesianSuffixForms(word) {
+		if isIndonesianRoot(form) {
+			return form, true
+		}
+	}
+	return "", false
+}
+
+// idPrefixCut is a way to remove a prefix from a word.
+type idPrefixCut struct {
+	prefix string
+	rest   string
+}
+
+// removeIDPrefixes removes up to three prefixes from word until found accepts what is left.
+// The same prefix is never removed twice in a row.
+func removeIDPrefixes(word string, depth int, prev string, found func(string) (string, bool)) (string, bool) {
+	if depth == 3 {
+		return "", false
+	}
+	cuts := indonesianPrefixCuts(word)
+	for _, cut := range cuts {
+		if cut.prefix == prev {
+			continue
+		}
+		if root, ok := found(cut.rest); ok {
+			return root, true
+		}
+	}
+	for _, cut := range cuts {
+		if cut.prefix == prev {
+			continue
+		}
+		if root, ok := removeIDPrefixes(cut.rest, depth+1, cut.prefix, found); ok {
+			return root, true
+		}
+	}
+	return "", false
+}
+
+func isVowel(b byte) bool {
+	return b == 'a' || b == 'i' || b == 'u' || b == 'e' || b == 'o'
+}
+
+// startsWith reports whether the first byte of s is one of chars.
+func startsWith(s string, chars string) bool {
+	return s != "" && strings.IndexByte(chars, s[0]) >= 0
+}
+
+func startsWithVowel(s string) bool {
+	return s != "" && isVowel(s[0])
+}
+
+// indonesianPrefixCuts returns the possible ways to remove the prefix of word,
+// following the disambiguation rules of the be-, te-, me- and pe- prefixes.
+func indonesianPrefixCuts(word string) []idPrefixCut {
+	if len(word) < 4 {
+		return nil
+	}
+	cut := func(prefix string, rests ...string) []idPrefixCut {
+		cuts := make([]idPrefixCut, len(rests))
+		for i, rest := range rests {
+			cuts[i] = idPrefixCut{prefix, rest}
+		}
+		return cuts
+	}
+
+	switch {
+	case strings.HasPrefix(word, "di"), strings.HasPrefix(word, "ke"), strings.HasPrefix(word, "se"):
+		return cut(word[:2], word[2:])
+
+	case strings.HasPrefix(word, "ber"):
+		// berV -> ber-V | be-rV, berCAP -> ber-CAP
+		rest := word[3:]
+		if startsWithVowel(rest) {
+			return cut("be", rest, "r"+rest)
+		}
+		return cut("be", rest)
+	case strings.HasPrefix(word, "belajar"):
+		return cut("be", word[3:])
+	case strings.HasPrefix(word, "be") && !startsWith(word[2:], "aiueor") && strings.HasPrefix(word[3:], "er"):
+		// beC1erC2 -> be-C1erC2
+		return cut("be", word[2:])
+
+	case strings.HasPrefix(word, "ter"):
+		// terV -> ter-V | te-rV, terCP -> ter-CP
+		rest := word[3:]
+		if startsWithVowel(rest) {
+			return cut("te", rest, "r"+rest)
+		}
+		return cut("te", rest)
+	case strings.HasPrefix(word, "te") && !startsWith(word[2:], "aiueor") && strings.HasPrefix(word[3:], "er"):
+		// teC1erC2 -> te-C1erC2
+		return cut("te", word[2:])
+
+	case strings.HasPrefix(word, "meng"):
+		return nasalCuts("me", word[4:])
+	case strings.HasPrefix(word, "meny"):
+		// menyV -> meny-sV | me-nyV
+		if rest := word[4:]; startsWithVowel(rest) {
+			return cut("me", "s"+rest, "ny"+rest)
+		}
+	case strings.HasPrefix(word, "mem"):
+		rest := word[3:]
+		switch {
+		case startsWith(rest, "bfvp"):
+			// mem{b,f,v} -> mem-{b,f,v}, mempV -> mem-pV
+			return cut("me", rest)
+		case startsWithVowel(rest), strings.HasPrefix(rest, "r") && startsWithVowel(rest[1:]):
+			// mem{rV,V} -> me-m{rV,V} | me-p{rV,V}
+			return cut("me", "m"+rest, "p"+rest)
+		}
+	case strings.HasPrefix(word, "men"):
+		rest := word[3:]
+		switch {
+		case startsWith(rest, "cdjstz"):
+			// men{c,d,j,s,t,z} -> men-{c,d,j,s,t,z}
+			return cut("me", rest)
+		case startsWithVowel(rest):
+			// menV -> me-nV | me-tV
+			return cut("me", "n"+rest, "t"+rest)
+		}
+	case strings.HasPrefix(word, "me") && startsWith(word[2:], "lrwy") && startsWithVowel(word[3:]):
+		// me{l,r,w,y}V -> me-{l,r,w,y}V
+		return cut("me", word[2:])
+
+	case strings.HasPrefix(word, "peng"):
+		return nasalCuts("pe", word[4:])
+	case strings.HasPrefix(word, "peny"):
+		// penyV -> peny-sV | pe-nyV
+		if rest := word[4:]; startsWithVowel(rest) {
+			return cut("pe", "s"+rest, "ny"+rest)
+		}
+	case strings.HasPrefix(word, "pem"):
+		rest := word[3:]
+		switch {
+		case startsWith(rest, "bfv"):
+			// pem{b,f,v} -> pem-{b,f,v}
+			return cut("pe", rest)
+		case startsWithVowel(rest), strings.HasPrefix(rest, "r") && startsWithVowel(rest[1:]):
+			// pem{rV,V} -> pe-m{rV,V} | pe-p{rV,V}
+			return cut("pe", "m"+rest, "p"+rest)
+		}
+	case strings.HasPrefix(word, "pen"):
+		rest := word[3:]
+		switch {
+		case startsWith(rest, "cdjstz"):
+			// pen{c,d,j,s,t,z} -> pen-{c,d,j,s,t,z}
+			return cut("pe", rest)
+		case startsWithVowel(rest):
+			// penV -> pe-nV | pe-tV
+			return cut("pe", "n"+rest, "t"+rest)
+		}
+	case strings.HasPrefix(word, "per"):
+		// perV -> per-V | pe-rV, perCAP -> per-CAP
+		rest := word[3:]
+		if startsWithVowel(rest) {
+			return cut("pe", rest, "r"+rest)
+		}
+		return cut("pe", rest)
+	case strings.HasPrefix(word, "pelajar"):
+		return cut("pe", word[3:])
+	case strings.HasPrefix(word, "pe") && startsWith(word[2:], "wy") && startsWithVowel(word[3:]):
+		// pe{w,y}V -> pe-{w,y}V
+		return cut("pe", word[2:])
+	case strings.HasPrefix(word, "pe") && !startsWith(word[2:], "aiueorwymn"):
+		// pelV -> pe-lV, peCP -> pe-CP, peC1erC2 -> pe-C1erC2
+		return cut("pe", word[2:])
+	}
+	return nil
+}
+
+// nasalCuts handles the meng- and peng- prefixes, rest is what follows "ng".
+func nasalCuts(prefix string, rest string) []idPrefixCut {
+	switch {
+	case startsWith(rest, "ghqk"):
+		// meng{g,h,q,k} -> meng-{g,h,q,k}
+		return []idPrefixCut{{prefix, rest}}
+	case startsWithVowel(rest):
+		// mengV -> meng-V | meng-kV, mengeC -> menge-C
+		cuts := []idPrefixCut{{prefix, rest}, {prefix, "k" + rest}}
+		if strings.HasPrefix(rest, "e") && len(rest) > 1 && !isVowel(rest[1]) {
+			cuts = append(cuts, idPrefixCut{prefix, rest[1:]})
+		}
+		return cuts
+	}
+	return nil
+}
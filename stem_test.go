@@ -0,0 +1,113 @@
+package tek_test
+
+import (
+	. "github.com/didasy/tek"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stemmer", func() {
+
+	Describe("Stemming Indonesian words", func() {
+		It("Should strip prefixes, suffixes and confixes", func() {
+			words := map[string]string{
+				"bergabung":     "gabung",
+				"menggulingkan": "guling",
+				"persidangan":   "sidang",
+				"penyerangan":   "serang",
+				"pengacara":     "acara",
+				"peningkatan":   "tingkat",
+				"kekhawatiran":  "khawatir",
+				"mempelajari":   "ajar",
+				"memperbaiki":   "baik",
+				"menyapu":       "sapu",
+				"mengecat":      "cat",
+				"menari":        "tari",
+				"kliennya":      "klien",
+				"diketahui":     "tahu",
+				"tertangkap":    "tangkap",
+			}
+			for word, root := range words {
+				Expect(StemID(word)).To(Equal(root), word)
+			}
+		})
+		It("Should stem reduplicated words", func() {
+			Expect(StemID("negara-negara")).To(Equal("negara"))
+			Expect(StemID("bermain-main")).To(Equal("main"))
+			Expect(StemID("bersenang-senang")).To(Equal("senang"))
+		})
+		It("Should keep roots and unknown words unchanged", func() {
+			Expect(StemID("penjara")).To(Equal("penjara"))
+			Expect(StemID("harun")).To(Equal("harun"))
+			Expect(StemID("al-assad")).To(Equal("al-assad"))
+		})
+	})
+
+	Describe("Stemming English words", func() {
+		It("Should follow the Porter2 algorithm", func() {
+			words := map[string]string{
+				"museums":       "museum",
+				"architects":    "architect",
+				"designs":       "design",
+				"designed":      "design",
+				"consolidating": "consolid",
+				"generously":    "generous",
+				"knitting":      "knit",
+				"hoped":         "hope",
+				"ponies":        "poni",
+				"ties":          "tie",
+				"gas":           "gas",
+				"relational":    "relat",
+				"effective":     "effect",
+				"replacement":   "replac",
+				"skies":         "sky",
+				"dying":         "die",
+				"succeeding":    "succeed",
+			}
+			for word, stem := range words {
+				Expect(StemEN(word)).To(Equal(stem), word)
+			}
+		})
+		It("Should keep words with other letters unchanged", func() {
+			Expect(StemEN("rä")).To(Equal("rä"))
+			Expect(StemEN("cédric")).To(Equal("cédric"))
+		})
+	})
+
+	Describe("Tagging English text", func() {
+		It("Should report the most frequent form instead of the stem", func() {
+			tagger, _ := New(WithLang("en"))
+			tags := tagger.GetTags(string(sample), 5)
+			Expect(terms(tags)).To(ContainElement("wars"))
+			Expect(terms(tags)).To(ContainElement("architects"))
+			for _, tag := range tags {
+				Expect(tag.Term).ToNot(Equal("architect"))
+				Expect(tag.Term).ToNot(Equal("museums"))
+			}
+		})
+	})
+
+	Describe("Tagging Indonesian text", func() {
+		It("Should group the forms of a word under its root", func() {
+			tagger, _ := New(WithLang("id"))
+			tags := tagger.GetTags(string(indonesian), 10)
+			var serang *Info
+			for _, tag := range tags {
+				Expect(tag.Term).ToNot(Equal("penyerangan"))
+				if tag.Stem == "serang" {
+					serang = tag
+				}
+			}
+			Expect(serang).ToNot(BeNil())
+			Expect(serang.Term).To(Equal("serangan"))
+		})
+		It("Should not stem without a stemmer", func() {
+			tagger, _ := New(WithLang("id"), WithStemmer(nil))
+			for _, tag := range tagger.GetTags(string(indonesian), 10) {
+				Expect(tag.Stem).To(Equal(tag.Term))
+			}
+		})
+	})
+
+})
@@ -0,0 +1,76 @@
+package tek
+
+import "strings"
+
+// stopList tells the stop words of a language, matching the entries of more
+// than one word, like "in fact" or "oleh karena itu", word by word through a trie.
+type stopList struct {
+	words   map[string]bool
+	phrases *stopNode
+}
+
+// stopNode is a node of the trie of stop phrases, end tells whether a phrase ends there.
+type stopNode struct {
+	next map[string]*stopNode
+	end  bool
+}
+
+// newStopList lowercases and cleans the words of entries as the words of a text are, so
+// "I think" and "what's more" match.
+func newStopList(entries []string) *stopList {
+	s := &stopList{words: make(map[string]bool, len(entries)), phrases: &stopNode{}}
+	for _, entry := range entries {
+		words := strings.Fields(entry)
+		for i, word := range words {
+			words[i] = cleanWord(strings.ToLower(word))
+		}
+		switch len(words) {
+		case 0:
+		case 1:
+			s.words[words[0]] = true
+		default:
+			node := s.phrases
+			for _, word := range words {
+				if node.next == nil {
+					node.next = make(map[string]*stopNode)
+				}
+				child := node.next[word]
+				if child == nil {
+					child = &stopNode{}
+					node.next[word] = child
+				}
+				node = child
+			}
+			node.end = true
+		}
+	}
+	return s
+}
+
+// isStop reports whether word is a stop word on its own.
+func (s *stopList) isStop(word string) bool {
+	return s != nil && s.words[word]
+}
+
+// phraseAt returns the number of words of the longest stop phrase starting at
+// words[i], 0 if there is none. A phrase never crosses a clause boundary.
+func (s *stopList) phraseAt(words []string, breaks []bool, i int) int {
+	if s == nil {
+		return 0
+	}
+	n := 0
+	node := s.phrases
+	for j := i; j < len(words); j++ {
+		node = node.next[words[j]]
+		if node == nil {
+			break
+		}
+		if node.end {
+			n = j - i + 1
+		}
+		if breaks[j] {
+			break
+		}
+	}
+	return n
+}
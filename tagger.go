@@ -0,0 +1,547 @@
+package tek
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Tagger holds everything needed to tag a text: language, stop words, POS
+// dictionary and POS modifiers. A Tagger is safe for concurrent use, so one
+// value per language can be shared by any number of goroutines.
+type Tagger struct {
+	mu sync.RWMutex
+	settings
+}
+
+// settings are copied at the start of each run, so a concurrent SetLang can't change them mid-run.
+type settings struct {
+	lang      string
+	stopWords []string
+	stopList  *stopList
+	// abbreviations that don't end a sentence, see SplitSentences
+	abbreviations map[string]abbreviation
+	tokenizer     Tokenizer
+	pos           lexicon
+	posHint       func(form, stem string) string
+	modifier      map[string]float64
+	stemmer       Stemmer
+	idfModel      *IDFModel
+	numWorkers    int
+	algorithm     Algorithm
+	// co-occurrence window of TextRank
+	window int
+	// longest phrase scored as a single tag, 1 means words only and 0 not set, see phraseLen
+	maxPhraseLen       int
+	suppressComponents bool
+	explain            bool
+	// detect the language of each text, see SetLang
+	auto bool
+	// detect the language of each sentence, see WithMixedLang
+	mixed bool
+	// trade-off between score and diversity of MMR, 1 turns it off
+	lambda float64
+}
+
+// Option configures a Tagger built by New.
+type Option func(*Tagger) error
+
+// WithLang sets the language of the Tagger, see SetLang.
+func WithLang(l string) Option {
+	return func(t *Tagger) error {
+		return t.setLang(l)
+	}
+}
+
+// WithStopWords replaces the stop words of the Tagger. Entries of several
+// words, like "in fact", are removed from the text as a whole.
+func WithStopWords(s []string) Option {
+	return func(t *Tagger) error {
+		t.setStopWords(s)
+		return nil
+	}
+}
+
+// WithPOS replaces the POS dictionary of the Tagger.
+func WithPOS(pos []*Vocab) Option {
+	return func(t *Tagger) error {
+		t.setPOS(pos)
+		return nil
+	}
+}
+
+// WithModifiers replaces the weight added for each POS type, see DefaultModifiers.
+// A later WithLang for a language with modifiers of its own replaces them.
+func WithModifiers(m map[string]float64) Option {
+	return func(t *Tagger) error {
+		t.modifier = copyModifier(m)
+		return nil
+	}
+}
+
+// WithStemmer sets the Stemmer used to group the forms of a word, nil turns stemming off.
+// SetLang uses StemID for "id" and StemEN for "en".
+func WithStemmer(s Stemmer) Option {
+	return func(t *Tagger) error {
+		t.stemmer = s
+		return nil
+	}
+}
+
+// WithTokenizer sets the Tokenizer splitting sentences into words,
+// nil goes back to DefaultTokenizer with Kinds set.
+func WithTokenizer(tz Tokenizer) Option {
+	return func(t *Tagger) error {
+		if tz == nil {
+			tz = DefaultTokenizer{Kinds: true}
+		}
+		t.tokenizer = tz
+		return nil
+	}
+}
+
+// WithIDFModel makes the Tagger use the document frequencies of a corpus.
+// Without a model, or with an empty one, IDF is computed from the sentences of each text.
+func WithIDFModel(m *IDFModel) Option {
+	return func(t *Tagger) error {
+		t.idfModel = m
+		return nil
+	}
+}
+
+// WithMaxPhraseLen lets the Tagger return phrases of up to n words, like
+// "star wars", scored together with single words. Phrases never contain a
+// stop word or cross a sentence, and must occur at least twice. Defaults to 1,
+// or 3 with AlgorithmTextRank.
+func WithMaxPhraseLen(n int) Option {
+	return func(t *Tagger) error {
+		if n < 1 {
+			n = 1
+		}
+		t.maxPhraseLen = n
+		return nil
+	}
+}
+
+// WithSuppressComponents stops the words of a chosen phrase from also being
+// returned as tags of their own, e.g. "star" and "wars" once "star wars" is chosen.
+func WithSuppressComponents(suppress bool) Option {
+	return func(t *Tagger) error {
+		t.suppressComponents = suppress
+		return nil
+	}
+}
+
+// WithAlgorithm sets the Algorithm used to score tags, AlgorithmTFIDF by default.
+func WithAlgorithm(a Algorithm) Option {
+	return func(t *Tagger) error {
+		t.algorithm = a
+		return nil
+	}
+}
+
+// WithWindow sets how many consecutive words are linked in the TextRank graph, 2 by default.
+func WithWindow(n int) Option {
+	return func(t *Tagger) error {
+		if n < 2 {
+			n = 2
+		}
+		t.window = n
+		return nil
+	}
+}
+
+// WithDiversity reorders the best tags by Maximal Marginal Relevance, so
+// near-duplicates like "museum" and "museums" don't both take a place in the
+// result. lambda weighs the score of a tag against its similarity to the tags
+// chosen before it, 1 keeps the order of Score and 0 only cares about
+// diversity. Similarity comes from shared words and shared sentences.
+func WithDiversity(lambda float64) Option {
+	return func(t *Tagger) error {
+		t.lambda = math.Max(0, math.Min(1, lambda))
+		return nil
+	}
+}
+
+// WithMixedLang detects the language of each sentence among "id" and "en",
+// and uses its stop words, stemmer and POS dictionary on the sentence, so the
+// English quoted in an Indonesian article doesn't end up as tags. Sentences too
+// short to tell keep the language of the Tagger, and Info.Lang tells where
+// each tag came from.
+func WithMixedLang(mixed bool) Option {
+	return func(t *Tagger) error {
+		t.mixed = mixed
+		return nil
+	}
+}
+
+// WithExplain attaches the Explanation of its score to each tag.
+func WithExplain(explain bool) Option {
+	return func(t *Tagger) error {
+		t.explain = explain
+		return nil
+	}
+}
+
+// WithWorkers sets the default number of workers used by GetTags.
+// If n is 0 or negative, the number of available CPU cores is used.
+func WithWorkers(n int) Option {
+	return func(t *Tagger) error {
+		t.numWorkers = n
+		return nil
+	}
+}
+
+// New returns a Tagger for english text, then applies opts in order.
+func New(opts ...Option) (*Tagger, error) {
+	t := &Tagger{settings: settings{
+		modifier:  copyModifier(defaultModifier),
+		tokenizer: DefaultTokenizer{Kinds: true},
+		window:    2,
+		lambda:    1,
+	}}
+	t.setLang("en")
+	for _, opt := range opts {
+		if err := opt(t); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Lang returns the language currently used by the Tagger.
+func (t *Tagger) Lang() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.lang
+}
+
+// SetLang sets the language used by the Tagger, "id", "en" or any added with
+// RegisterLanguage, or "auto" to detect the language of each text among them
+// and use its stop words, POS dictionary and stemmer, replacing any set with
+// the options. The POS modifiers are replaced only if the language has some.
+// Any other language returns an error wrapping ErrUnsupportedLang and leaves the Tagger unchanged.
+func (t *Tagger) SetLang(l string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.setLang(l)
+}
+
+// SetStopWords replaces the stop words used by the Tagger, see WithStopWords.
+func (t *Tagger) SetStopWords(s []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.setStopWords(s)
+}
+
+// SetPOS replaces the POS dictionary used by the Tagger.
+func (t *Tagger) SetPOS(pos []*Vocab) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.setPOS(pos)
+}
+
+// SetModifiers replaces the weight added for each POS type, see DefaultModifiers.
+func (t *Tagger) SetModifiers(m map[string]float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.modifier = copyModifier(m)
+}
+
+// SetIDFModel replaces the corpus model used for IDF, nil goes back to in-document IDF.
+func (t *Tagger) SetIDFModel(m *IDFModel) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.idfModel = m
+}
+
+// Train adds text to m as one document, using the same terms the Tagger scores.
+// A text without any word, like an empty one, isn't counted.
+func (t *Tagger) Train(m *IDFModel, text string) {
+	cfg, _ := t.config(text)
+	createSentencesChan := make(chan *document, 1)
+	createSentences(text, &cfg, createSentencesChan)
+	doc := <-createSentencesChan
+	terms := make([]string, 0, len(doc.index))
+	for term := range doc.index {
+		terms = append(terms, term)
+	}
+	m.AddDocument(terms)
+}
+
+func (cfg *settings) setLang(l string) error {
+	if l == "auto" {
+		// the resources are chosen for each text, see config
+		cfg.auto = true
+		cfg.lang = l
+		return nil
+	}
+	lang := lookupLanguage(l)
+	if lang == nil {
+		return fmt.Errorf("%w: %q", ErrUnsupportedLang, l)
+	}
+	// the tables of a language are shared, never written to
+	cfg.stopWords = lang.pack.StopWords
+	cfg.stopList = lang.stopList
+	cfg.pos = lang.lexicon()
+	cfg.posHint = lang.pack.POSHint
+	cfg.stemmer = lang.pack.Stemmer
+	if lang.pack.Modifiers != nil {
+		cfg.modifier = lang.pack.Modifiers
+	}
+	cfg.abbreviations = lang.abbreviations
+	cfg.auto = false
+	cfg.lang = l
+	return nil
+}
+
+// phraseLen returns the longest phrase scored as a single tag, words only
+// unless set with WithMaxPhraseLen, but TextRank merges phrases by default.
+func (cfg *settings) phraseLen() int {
+	if cfg.maxPhraseLen > 0 {
+		return cfg.maxPhraseLen
+	}
+	if cfg.algorithm == AlgorithmTextRank {
+		return textRankMaxPhraseLen
+	}
+	return 1
+}
+
+func (cfg *settings) setStopWords(s []string) {
+	cfg.stopWords = s
+	cfg.stopList = newStopList(s)
+}
+
+func (cfg *settings) setPOS(pos []*Vocab) {
+	cfg.pos = newVocabMap(pos)
+}
+
+func copyModifier(m map[string]float64) map[string]float64 {
+	c := make(map[string]float64, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// GetTags returns a slice of *Info struct, sorted by their weight descending.
+func (t *Tagger) GetTags(text string, num int) []*Info {
+	return t.GetTagsWithWorkers(text, num, t.workers())
+}
+
+// GetTagsWithWorkers allows specifying the number of workers for concurrent processing.
+// If numWorkers is 0 or negative, it defaults to the number of available CPU cores.
+// Invalid input gives an empty slice, use GetTagsContext to get the error instead.
+func (t *Tagger) GetTagsWithWorkers(text string, num int, numWorkers int) []*Info {
+	res, err := t.analyze(context.Background(), text, num, numWorkers)
+	if err != nil {
+		return []*Info{}
+	}
+	return res.Tags
+}
+
+// GetTagsContext is like GetTags, but stops its workers and returns ctx.Err()
+// once ctx is cancelled or times out. It returns ErrEmptyInput if text has no
+// content and ErrInvalidNum if num is not positive.
+func (t *Tagger) GetTagsContext(ctx context.Context, text string, num int) ([]*Info, error) {
+	res, err := t.analyze(ctx, text, num, t.workers())
+	if err != nil {
+		return nil, err
+	}
+	return res.Tags, nil
+}
+
+// Result is the tags of a text, with the language they were found in.
+type Result struct {
+	Tags []*Info
+	// the language of the Tagger, or the registered language the text is closest to if it is "auto"
+	Lang string
+	// how sure the detection is, from 0 to 1, always 1 if the language wasn't detected
+	// and 0 if the text has no letters to tell, which is tagged as English
+	Confidence float64
+}
+
+// Analyze is like GetTagsContext, but also reports the language of text.
+func (t *Tagger) Analyze(ctx context.Context, text string, num int) (*Result, error) {
+	return t.analyze(ctx, text, num, t.workers())
+}
+
+// config returns a copy of the settings to tag text with, using the language
+// detected in text if the Tagger is set to "auto", and the confidence of the detection.
+// The text is detected among the registered languages only, as those are the ones
+// with resources to tag it, so Indonesian close to Malay is still tagged as Indonesian.
+func (t *Tagger) config(text string) (settings, float64) {
+	cfg := t.snapshot()
+	if !cfg.auto {
+		return cfg, 1
+	}
+	lang, confidence := detectLang(text, detectableLangs())
+	if lang == "" {
+		// no letters to tell, tag it as English like a Tagger without a language
+		lang, confidence = "en", 0
+	}
+	cfg.setLang(lang)
+	return cfg, confidence
+}
+
+func (t *Tagger) workers() int {
+	return t.snapshot().numWorkers
+}
+
+func (t *Tagger) snapshot() settings {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.settings
+}
+
+func (t *Tagger) analyze(ctx context.Context, text string, num int, numWorkers int) (*Result, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrEmptyInput
+	}
+	if num <= 0 {
+		return nil, ErrInvalidNum
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cfg, confidence := t.config(text)
+
+	// buffered so an early return doesn't leak the goroutine
+	createSentencesChan := make(chan *document, 1)
+	go createSentences(text, &cfg, createSentencesChan)
+	var doc *document
+	select {
+	case doc = <-createSentencesChan:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	// end
+
+	// Use worker pools for better concurrency
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+
+	var termsInfo []*Info
+	var err error
+	switch cfg.algorithm {
+	case AlgorithmTextRank:
+		termsInfo, err = textRank(ctx, doc, cfg.window, cfg.phraseLen())
+	case AlgorithmRAKE:
+		termsInfo, err = rake(ctx, doc)
+	case AlgorithmYAKE:
+		termsInfo, err = yake(ctx, doc)
+	default:
+		seq := append(doc.candidateWords(), doc.candidatePhrases()...)
+		termsInfo, err = scoreTfidf(ctx, doc, seq, &cfg, numWorkers)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort only once using sort.SliceStable (remove the insertion sort)
+	sort.SliceStable(termsInfo, func(i, j int) bool {
+		return termsInfo[i].Score > termsInfo[j].Score
+	})
+
+	if cfg.lambda < 1 {
+		termsInfo = diversify(termsInfo, num, doc, cfg.lambda)
+	}
+
+	// return only N number of tags
+	tags := selectTags(termsInfo, num, cfg.suppressComponents)
+	for _, tag := range tags {
+		tag.Display = doc.display(tag.Stem)
+		tag.Lang = doc.language(tag.Stem)
+		tag.Spans, tag.Sentences = doc.occurrences(tag.Stem)
+		if cfg.explain {
+			tag.Explanation = doc.explain(tag, &cfg)
+		}
+	}
+	return &Result{Tags: tags, Lang: cfg.lang, Confidence: confidence}, nil
+}
+
+// scoreTfidf scores the candidate terms of seq by TF-IDF, reweighted by POS if there is a POS dictionary.
+func scoreTfidf(ctx context.Context, doc *document, seq []string, cfg *settings, numWorkers int) ([]*Info, error) {
+	if len(seq) < numWorkers {
+		numWorkers = len(seq)
+	}
+
+	// Parallel IDF calculation with worker pool
+	termsInfo := make([]*Info, len(seq))
+	err := runPool(ctx, len(seq), numWorkers, func(idx int) {
+		findIdf(idx, termsInfo, doc, cfg.idfModel, seq[idx])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Parallel TF-IDF calculation with worker pool
+	err = runPool(ctx, len(termsInfo), numWorkers, func(idx int) {
+		findTfidf(idx, termsInfo, doc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.hasPOS() {
+		// Parallel POS modification with worker pool, each term by the POS dictionary of its language
+		err = runPool(ctx, len(termsInfo), numWorkers, func(idx int) {
+			stem := termsInfo[idx].Stem
+			modifyTfidfId(idx, termsInfo, doc.lexicon(stem), doc.modifier(stem), doc.posContext(stem))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, info := range termsInfo {
+		info.Score = info.Tfidf
+	}
+	return termsInfo, nil
+}
+
+// runPool calls fn for every index in [0, n) using numWorkers goroutines.
+// It stops handing out jobs once ctx is done, waits for the running ones
+// and returns ctx.Err().
+func runPool(ctx context.Context, n int, numWorkers int, fn func(idx int)) error {
+	jobs := make(chan int)
+	done := make(chan bool, numWorkers)
+
+	// Start workers
+	for w := 0; w < numWorkers; w++ {
+		go func() {
+			for idx := range jobs {
+				fn(idx)
+			}
+			done <- true
+		}()
+	}
+
+	// Send jobs until ctx is done
+	var err error
+send:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break send
+		}
+	}
+	close(jobs)
+
+	// Wait for workers to complete
+	for i := 0; i < numWorkers; i++ {
+		<-done
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
@@ -1,12 +1,15 @@
 /*
 tek is an automatic tagging library for Go.
+
+The package level functions use a shared default Tagger. Programs that tag
+text in several languages at the same time should build their own Tagger
+values with New instead.
 */
 package tek
 
 import (
+	"context"
 	"math"
-	"runtime"
-	"sort"
 	"strings"
 	"unicode"
 )
@@ -15,14 +18,6 @@ const (
 	VERSION = "0.1.1"
 )
 
-func init() {
-	// Initialize default stop words map for English
-	stopWordsMap = make(map[string]bool, len(englishStopWords))
-	for _, word := range englishStopWords {
-		stopWordsMap[word] = true
-	}
-}
-
 // Comprehensive Indonesian stop words
 var indonesianStopWords []string = []string{
 	// Common function words
@@ -203,358 +198,235 @@ var englishStopWords []string = []string{
 	"work", "job", "career", "profession", "occupation", "business", "company", "corporation", "organization", "institution", "agency", "department", "office", "factory", "shop", "store", "market", "industry", "trade", "commerce", "economy", "finance", "money", "cash", "currency", "dollar", "pound", "euro", "yen", "price", "cost", "expense", "budget", "income", "salary", "wage", "pay", "earn", "profit", "loss", "gain", "investment", "saving", "spending", "buying", "selling", "shopping", "purchase", "sale", "discount", "bargain", "deal", "offer", "contract", "agreement", "negotiation", "meeting", "conference", "presentation", "report", "document", "file", "record", "database", "computer", "technology", "internet", "website", "email", "phone", "call", "message", "communication", "conversation", "discussion", "argument", "debate", "dispute", "conflict", "resolution", "solution", "answer", "question", "problem", "issue", "challenge", "opportunity", "success", "failure", "achievement", "accomplishment", "goal", "objective", "target", "purpose", "mission", "vision", "strategy", "plan", "project", "task", "duty", "responsibility", "obligation", "commitment", "dedication", "effort", "attempt", "try", "performance", "result", "outcome", "consequence", "effect", "impact", "influence", "change", "development", "progress", "improvement", "growth", "expansion", "increase", "decrease", "reduction", "decline", "rise", "fall", "growth", "shrink", "expand", "contract",
 }
 
-var lang string = "en"
-
-var stopWords []string = englishStopWords
-var stopWordsMap map[string]bool
-
-func SetStopWords(s []string) {
-	stopWords = s
-	stopWordsMap = make(map[string]bool, len(s))
-	for _, word := range s {
-		stopWordsMap[word] = true
-	}
-}
-
 // need to tweak these values later
 // var modifier map[string]float64 = map[string]float64{ "nama": 2.5, "nomina" : 1.75, "verba" : 1, "adjektiva" : 0.5, "adverbia" : 0.75, "numeralia" : 0.5 }
-var modifier map[string]float64 = map[string]float64{"nama": 3.5, "nomina": 3.0, "verba": 2.0, "adjektiva": 1.0, "adverbia": 0.25, "numeralia": 0.5}
+var defaultModifier map[string]float64 = map[string]float64{POSName: 3.5, POSNoun: 3.0, POSVerb: 2.0, POSAdjective: 1.0, POSAdverb: 0.25, POSNumeral: 0.5}
 
 type Vocab struct {
 	Id   int    `json:"id"`
 	Word string `json:"word"`
+	// the most common POS type of the word
 	Type string `json:"type"`
+	// every POS type of a word used in several ways, like "serang", leave it empty for a word with a single Type
+	Types []POSType `json:"types,omitempty"`
+}
+
+// POSType is one of the POS types of a word, with how often the word is used that way, 0 if unknown.
+type POSType struct {
+	Type string  `json:"type"`
+	Freq float64 `json:"freq,omitempty"`
 }
 
-var pos []*Vocab
-var posMap map[string]*Vocab
+// defaultTagger backs the package level functions.
+var defaultTagger *Tagger
 
-// Set language used, defaulted to english if not called. If argument is not "id" or "en", empty stop words will be used
-// For now only support Indonesian and English stop words
+func init() {
+	defaultTagger, _ = New()
+}
+
+// Set stop words used by the package level functions.
+func SetStopWords(s []string) {
+	defaultTagger.SetStopWords(s)
+}
+
+// Set language used by the package level functions, defaulted to english if not called.
+// Supports "id", "en" and any language added with RegisterLanguage, or "auto" to detect
+// which of them each text is in, any other language returns an error wrapping ErrUnsupportedLang
 func SetLang(l string) error {
-	switch l {
-	case "id":
-		stopWords = indonesianStopWords
-		pos = indonesianPos
-		// Build POS map for O(1) lookup
-		posMap = make(map[string]*Vocab, len(indonesianPos))
-		for _, vocab := range indonesianPos {
-			posMap[vocab.Word] = vocab
-		}
-		// Build stop words map
-		stopWordsMap = make(map[string]bool, len(indonesianStopWords))
-		for _, word := range indonesianStopWords {
-			stopWordsMap[word] = true
-		}
-		break
-	case "en":
-		stopWords = englishStopWords
-		// Build stop words map for English
-		stopWordsMap = make(map[string]bool, len(englishStopWords))
-		for _, word := range englishStopWords {
-			stopWordsMap[word] = true
-		}
-		posMap = nil
-		break
-	default:
-		// if undefined language, use empty stopwords
-		stopWords = []string{}
-		stopWordsMap = make(map[string]bool)
-		posMap = nil
-		break
-	}
-	lang = l
-	return nil
+	return defaultTagger.SetLang(l)
 }
 
-func findIdf(idx int, termsInfo []*Info, sentences [][]string, termsCount float64, term string) {
-	count := 0.0
-	for _, sen := range sentences {
-		found := false
-		for _, word := range sen {
-			if term == word {
-				found = true
-				// Don't break here - the original logic didn't break
-			}
-		}
-		if found {
-			count++
-		}
+// Analyze returns the tags of text with its language, see Tagger.Analyze.
+func Analyze(ctx context.Context, text string, num int) (*Result, error) {
+	return defaultTagger.Analyze(ctx, text, num)
+}
+
+// findIdf uses the corpus frequencies of model if there is one, else the sentences of doc.
+func findIdf(idx int, termsInfo []*Info, doc *document, model *IDFModel, stem string) {
+	idf := 0.0
+	if model != nil && model.Docs() > 0 {
+		idf = model.IDF(stem)
+	} else if stat := doc.index[stem]; stat != nil {
+		idf = math.Log(float64(doc.words) / float64(len(stat.sentences)))
 	}
-	idf := math.Log(termsCount / count)
-	termsInfo[idx] = &Info{term, idf, 0.0, 0.0}
+	termsInfo[idx] = &Info{Term: doc.form(stem), Stem: stem, Kind: doc.kind(stem), Idf: idf}
 }
 
-func findTfidf(idx int, termsInfo []*Info, termsCount float64, sentences [][]string) {
+func findTfidf(idx int, termsInfo []*Info, doc *document) {
 	count := 0.0
-	term := termsInfo[idx].Term
-	for _, sen := range sentences {
-		for _, word := range sen {
-			word = sanitizeWord(word) // Restore sanitizeWord call for correctness
-			if term == word {
-				count++
-			}
-		}
+	if stat := doc.index[termsInfo[idx].Stem]; stat != nil {
+		count = float64(stat.freq)
 	}
-	termsInfo[idx].Tf = count / termsCount
-	termsInfo[idx].Tfidf = termsInfo[idx].Tf * termsInfo[idx].Idf
+	termsInfo[idx].Tf = count / float64(doc.words)
+	// a phrase occurs less often than its words, weight it by its length so it can compete with them
+	termsInfo[idx].Tfidf = termsInfo[idx].Tf * termsInfo[idx].Idf * float64(phraseLen(termsInfo[idx].Stem))
 }
 
-func modifyTfidfId(idx int, termsInfo []*Info, posMap map[string]*Vocab) {
-	term := termsInfo[idx].Term
-	found := false
-	for _, vocab := range pos { // Use original pos array for exact same behavior
-		if term != vocab.Word {
-			termsInfo[idx].Tfidf += termsInfo[idx].Tfidf * modifier["nama"]
-			found = true
-			break
-		}
-		if term == vocab.Word {
-			// Apply the exact same logic as original
-			if vocab.Type != "lain-lain" {
-				termsInfo[idx].Tfidf += termsInfo[idx].Tfidf * modifier[vocab.Type]
-			}
-			if vocab.Type != "pronomina" {
-				termsInfo[idx].Tfidf += termsInfo[idx].Tfidf * modifier[vocab.Type]
-			}
-			if vocab.Type != "interjeksi" {
-				termsInfo[idx].Tfidf += termsInfo[idx].Tfidf * modifier[vocab.Type]
-			}
-			if vocab.Type != "preposisi" {
-				termsInfo[idx].Tfidf += termsInfo[idx].Tfidf * modifier[vocab.Type]
-			}
-			found = true
-			break
-		}
-	}
-	// If word not found in POS dictionary at all
-	if !found {
-		termsInfo[idx].Tfidf += termsInfo[idx].Tfidf * modifier["nama"]
+// modifyTfidfId reweights a term once by its POS type, looked up in lex.
+// Terms of a language without a POS dictionary are left as is.
+func modifyTfidfId(idx int, termsInfo []*Info, lex lexicon, modifier map[string]float64, ctx *posContext) {
+	if lex == nil {
+		return
 	}
+	termsInfo[idx].Tfidf *= posWeight(termsInfo[idx].Stem, lex, modifier, ctx)
 }
 
 type Info struct {
-	Term  string
+	// the most frequent form of the tag in the text
+	Term string
+	// Term as it is most often written, e.g. "MAD" or "Star Wars"
+	Display string
+	// language of the sentences the tag mostly occurs in
+	Lang string
+	// byte offsets of each occurrence of the tag in the text
+	Spans []Span
+	// index of the sentence of each span, among the sentences given by SplitSentences
+	Sentences []int
+	// the key the tag was scored under, all the forms of Term share it
+	Stem string
+	// KindHashtag, KindMention or KindVersion for those tokens, KindWord otherwise
+	Kind  Kind
 	Idf   float64
 	Tf    float64
 	Tfidf float64
+	// the weight tags are sorted by, the same as Tfidf unless another Algorithm is used
+	Score float64
+	// how Score was made, only set WithExplain
+	Explanation Explanation
 }
 
 // The main method of this package, return a slice of *Info struct, sorted by their weight descending.
 func GetTags(text string, num int) []*Info {
-	return GetTagsWithWorkers(text, num, runtime.NumCPU())
+	return defaultTagger.GetTags(text, num)
 }
 
 // GetTagsWithWorkers allows specifying the number of workers for concurrent processing.
 // If numWorkers is 0 or negative, it defaults to the number of available CPU cores.
 func GetTagsWithWorkers(text string, num int, numWorkers int) []*Info {
-	// sequential ops, cannot go parallel
-	dict := createDictionary(text)
-	seq := createSeqDict(dict)
-	// we could go concurrent here
-	rmStopWordsChan := make(chan []string)
-	createSentencesChan := make(chan [][]string)
-	defer close(rmStopWordsChan)
-	defer close(createSentencesChan)
-	go removeStopWords(seq, stopWordsMap, rmStopWordsChan)
-	go createSentences(text, createSentencesChan)
-	sens := <-createSentencesChan
-	seq = <-rmStopWordsChan
-	// end
-	termsCount := float64(len(flatten(sens)))
-
-	// Use worker pools for better concurrency
-	if numWorkers <= 0 {
-		numWorkers = runtime.NumCPU()
-	}
-	if len(seq) < numWorkers {
-		numWorkers = len(seq)
-	}
-
-	// Parallel IDF calculation with worker pool
-	termsInfo := make([]*Info, len(seq))
-	idfJobs := make(chan int, len(seq))
-	idfDone := make(chan bool, numWorkers)
-
-	// Start IDF workers
-	for w := 0; w < numWorkers; w++ {
-		go func() {
-			for idx := range idfJobs {
-				findIdf(idx, termsInfo, sens, termsCount, seq[idx])
-			}
-			idfDone <- true
-		}()
-	}
-
-	// Send jobs
-	for i := range seq {
-		idfJobs <- i
-	}
-	close(idfJobs)
-
-	// Wait for workers to complete
-	for i := 0; i < numWorkers; i++ {
-		<-idfDone
-	}
-
-	// Parallel TF-IDF calculation with worker pool
-	tfidfJobs := make(chan int, len(termsInfo))
-	tfidfDone := make(chan bool, numWorkers)
-
-	// Start TF-IDF workers
-	for w := 0; w < numWorkers; w++ {
-		go func() {
-			for idx := range tfidfJobs {
-				findTfidf(idx, termsInfo, termsCount, sens)
-			}
-			tfidfDone <- true
-		}()
-	}
-
-	// Send jobs
-	for i := range termsInfo {
-		tfidfJobs <- i
-	}
-	close(tfidfJobs)
-
-	// Wait for workers to complete
-	for i := 0; i < numWorkers; i++ {
-		<-tfidfDone
-	}
-
-	if lang == "id" {
-		// Parallel Indonesian POS modification with worker pool
-		posJobs := make(chan int, len(termsInfo))
-		posDone := make(chan bool, numWorkers)
-
-		// Start POS workers
-		for w := 0; w < numWorkers; w++ {
-			go func() {
-				for idx := range posJobs {
-					modifyTfidfId(idx, termsInfo, posMap)
-				}
-				posDone <- true
-			}()
-		}
-
-		// Send jobs
-		for i := range termsInfo {
-			posJobs <- i
-		}
-		close(posJobs)
-
-		// Wait for workers to complete
-		for i := 0; i < numWorkers; i++ {
-			<-posDone
-		}
-	}
-
-	// Sort only once using sort.SliceStable (remove the insertion sort)
-	sort.SliceStable(termsInfo, func(i, j int) bool {
-		return termsInfo[i].Tfidf > termsInfo[j].Tfidf
-	})
-
-	// out of range error guard
-	if num >= len(termsInfo) {
-		num = len(termsInfo)
-	}
-
-	// return only N number of tags
-	result := make([]*Info, num)
-	copy(result, termsInfo[:num])
-	return result
+	return defaultTagger.GetTagsWithWorkers(text, num, numWorkers)
 }
 
-func flatten(sens [][]string) []string {
-	var flat []string
-	for _, v := range sens {
-		flat = append(flat, v...)
-	}
-	return flat
+// GetTagsContext is like GetTags, but can be cancelled through ctx and reports invalid input as an error.
+func GetTagsContext(ctx context.Context, text string, num int) ([]*Info, error) {
+	return defaultTagger.GetTagsContext(ctx, text, num)
 }
 
-func createSentences(text string, createSentencesChan chan<- [][]string) {
-	text = strings.TrimSpace(text)
-	words := strings.Fields(text)
-	var sentence []string
-	var sentences [][]string
-	for _, word := range words {
-		// lowercase them FIX 1
-		word = strings.ToLower(word)
-		// if there isn't . ? or !, append to sentence. If found, also append (and remove the non alphanumerics) but reset the sentence
-		if strings.ContainsRune(word, '.') || strings.ContainsRune(word, '!') || strings.ContainsRune(word, '?') {
-			word = strings.Map(func(r rune) rune {
-				if r == '.' || r == '!' || r == '?' {
-					return -1
-				}
-				return r
-			}, word)
-			// sanitize them FIX 2
-			word = sanitizeWord(word)
-			// Skip empty strings
-			if word != "" {
-				sentence = append(sentence, word)
+// createSentences splits text into sentences of terms made from the tokens of the tokenizer of cfg and indexes them.
+// It also records each word as written and the clause boundaries found after each word,
+// any punctuation between two words or a token that can't be a tag ends a clause.
+// Each word is stemmed and marked as a stop word with the resources of the language of its sentence,
+// or of the quote it is in. Stop phrases like "in fact" are dropped.
+func createSentences(text string, cfg *settings, createSentencesChan chan<- *document) {
+	langs := newSentenceLangs(cfg)
+	doc := &document{kinds: make(map[string]Kind), resources: langs.langs, lang: langs.base.lang}
+	for si, sp := range splitSentences(text, cfg.abbreviations) {
+		sen := text[sp.Start:sp.End]
+		ends, resources := langs.spans(sen)
+		var sentence, keys, surface, wordLangs []string
+		var wordResources []*langResources
+		var brk, stops []bool
+		var spans []Span
+		prevEnd, k := 0, 0
+		for _, tok := range cfg.tokenizer.Tokenize(sen) {
+			for k+1 < len(ends) && tok.Start >= ends[k] {
+				k++
+			}
+			res := resources[k]
+			term := termOf(tok)
+			if len(brk) > 0 && (term == "" || tok.Kind != KindWord || (tok.Start > prevEnd && strings.TrimSpace(sen[prevEnd:tok.Start]) != "")) {
+				brk[len(brk)-1] = true
 			}
-			if len(sentence) > 0 {
-				sentences = append(sentences, sentence)
+			prevEnd = tok.End
+			// Skip what can't be a tag, but don't let a phrase jump over it
+			if term == "" {
+				continue
 			}
-			sentence = []string{}
-		} else {
-			// sanitize them FIX 2
-			word = sanitizeWord(word)
-			// Skip empty strings
-			if word != "" {
-				sentence = append(sentence, word)
+			sentence = append(sentence, term)
+			surface = append(surface, tok.Text)
+			spans = append(spans, Span{sp.Start + tok.Start, sp.Start + tok.End})
+			// hashtags, mentions and versions are tags of their own, never stemmed or part of a phrase
+			brk = append(brk, tok.Kind != KindWord)
+			if tok.Kind != KindWord {
+				doc.kinds[term] = tok.Kind
+				keys = append(keys, term)
+				stops = append(stops, false)
+			} else {
+				keys = append(keys, res.stem(term))
+				stops = append(stops, res.stopList.isStop(term))
 			}
+			wordLangs = append(wordLangs, res.lang)
+			wordResources = append(wordResources, res)
+		}
+		// drop the stop phrases, matched in the language of their first word, leaving a clause boundary in their place
+		kept := 0
+		for j := 0; j < len(sentence); j++ {
+			if n := wordResources[j].stopList.phraseAt(sentence, brk, j); n > 0 {
+				if kept > 0 {
+					brk[kept-1] = true
+				}
+				j += n - 1
+				continue
+			}
+			sentence[kept], keys[kept], surface[kept], spans[kept] = sentence[j], keys[j], surface[j], spans[j]
+			brk[kept], stops[kept], wordLangs[kept] = brk[j], stops[j], wordLangs[j]
+			kept++
+		}
+		sentence, keys, surface, spans = sentence[:kept], keys[:kept], surface[:kept], spans[:kept]
+		brk, stops, wordLangs = brk[:kept], stops[:kept], wordLangs[:kept]
+		if len(sentence) > 0 {
+			doc.sentences = append(doc.sentences, sentence)
+			doc.keys = append(doc.keys, keys)
+			doc.surfaces = append(doc.surfaces, surface)
+			doc.breaks = append(doc.breaks, brk)
+			doc.stops = append(doc.stops, stops)
+			doc.langs = append(doc.langs, wordLangs)
+			doc.all = append(doc.all, locatedSentence{keys: keys, spans: spans, index: si})
 		}
 	}
-	if len(sentence) > 0 {
-		sentences = append(sentences, sentence)
+
+	// drop repeated sentences
+	uniq := uniqSentences(doc.sentences)
+	for i, j := range uniq {
+		doc.sentences[i], doc.keys[i], doc.surfaces[i] = doc.sentences[j], doc.keys[j], doc.surfaces[j]
+		doc.breaks[i], doc.stops[i], doc.langs[i] = doc.breaks[j], doc.stops[j], doc.langs[j]
 	}
-	sentences = uniqSentences(sentences)
-	createSentencesChan <- sentences
+	n := len(uniq)
+	doc.sentences, doc.keys, doc.surfaces = doc.sentences[:n], doc.keys[:n], doc.surfaces[:n]
+	doc.breaks, doc.stops, doc.langs = doc.breaks[:n], doc.stops[:n], doc.langs[:n]
+	doc.buildIndex(cfg.phraseLen())
+	createSentencesChan <- doc
 }
 
-func uniqSentences(sentences [][]string) [][]string {
-	z := make([]string, len(sentences))
+// uniqSentences returns the indices of the first occurrence of each sentence.
+func uniqSentences(sentences [][]string) []int {
+	m := make(map[string]bool)
+	var uniq []int
 	for i, v := range sentences {
 		j := strings.Join(v, " ")
-		z[i] = j
-	}
-	m := make(map[string]bool)
-	var uniq []string
-	for _, v := range z {
-		if m[v] {
+		if m[j] {
 			continue
 		}
-		uniq = append(uniq, v)
-		m[v] = true
+		uniq = append(uniq, i)
+		m[j] = true
 	}
-	unique := make([][]string, len(uniq))
-	for i, v := range uniq {
-		unique[i] = strings.Fields(v)
-	}
-	return unique
+	return uniq
 }
 
-func removeStopWords(seq []string, stopWordsMap map[string]bool, rmStopWordsChan chan<- []string) {
-	// Pre-allocate result slice with estimated capacity
-	res := make([]string, 0, len(seq))
-	for _, v := range seq {
-		// Skip empty strings and stop words
-		if v != "" && !stopWordsMap[v] {
-			res = append(res, v)
-		}
+// termOf returns the lowercased text of tok, or "" if tok can't be a tag.
+func termOf(tok Token) string {
+	switch tok.Kind {
+	case KindNumber, KindURL, KindEmail:
+		return ""
 	}
-	rmStopWordsChan <- res
+	return strings.ToLower(tok.Text)
 }
 
-func sanitizeWord(word string) string {
-	word = strings.ToLower(word)
+// cleanWord removes all non alphanumerics from word but the hyphens inside it.
+func cleanWord(word string) string {
 	var prev rune
-	word = strings.Map(func(r rune) rune {
+	return strings.Map(func(r rune) rune {
 		// don't remove '-' if it exists after alphanumerics
-		if r == '-' && ((prev >= '0' && prev <= '9') || (prev >= 'a' && prev <= 'z') || prev == 'ä' || prev == 'ö' || prev == 'ü' || prev == 'ß' || prev == 'é') {
+		if r == '-' && isAlnum(prev) {
 			return r
 		}
 		if !unicode.IsDigit(r) && !unicode.IsLetter(r) && !unicode.IsSpace(r) {
@@ -563,13 +435,6 @@ func sanitizeWord(word string) string {
 		prev = r
 		return r
 	}, word)
-
-	// Exclude pure numbers from tagging
-	if word != "" && isNumeric(word) {
-		return ""
-	}
-
-	return word
 }
 
 // isNumeric checks if a string contains only digits (and optionally hyphens for number ranges)
@@ -590,48 +455,3 @@ func isNumeric(s string) bool {
 
 	return hasDigit
 }
-
-func createSeqDict(dict map[string]int) []string {
-	var seq []string
-	for term, _ := range dict {
-		seq = append(seq, term)
-	}
-	return seq
-}
-
-func createDictionary(text string) map[string]int {
-	// trim all spaces
-	text = strings.TrimSpace(text)
-	// lowercase the text
-	text = strings.ToLower(text)
-	// remove all non alphanumerics but spaces
-	var prev rune
-	text = strings.Map(func(r rune) rune {
-		// don't remove '-' if it exists after alphanumerics
-		if r == '-' && ((prev >= '0' && prev <= '9') || (prev >= 'a' && prev <= 'z') || prev == 'ä' || prev == 'ö' || prev == 'ü' || prev == 'ß' || prev == 'é') {
-			return r
-		}
-		if !unicode.IsDigit(r) && !unicode.IsLetter(r) && !unicode.IsSpace(r) {
-			return -1
-		}
-		prev = r
-		return r
-	}, text)
-	// TRYING TO FIX BUG : remove all double spaces left
-	text = strings.Replace(text, "  ", " ", -1)
-	// turn it into bag of words
-	words := strings.Fields(text)
-	// turn it into dictionary
-	dict := make(map[string]int)
-	i := 1
-	for _, word := range words {
-		// Sanitize the word to exclude numbers
-		word = sanitizeWord(word)
-		// Skip empty strings
-		if word != "" && dict[word] == 0 {
-			dict[word] = i
-			i++
-		}
-	}
-	return dict
-}
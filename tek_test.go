@@ -6,7 +6,13 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"bytes"
+	"context"
+	"errors"
 	"io/ioutil"
+	"math/rand"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -31,9 +37,9 @@ var _ = Describe("Tek", func() {
 
 	Describe("Testing the tagger", func() {
 		Context("Set language", func() {
-			It("Should not return error if language is not `en` or `id`", func() {
+			It("Should return ErrUnsupportedLang if language is not `en` or `id`", func() {
 				err := SetLang("us")
-				Expect(err).To(BeNil())
+				Expect(errors.Is(err, ErrUnsupportedLang)).To(BeTrue())
 			})
 			It("Should not return error if language is set to `id`", func() {
 				err := SetLang("id")
@@ -62,6 +68,41 @@ var _ = Describe("Tek", func() {
 				Expect(tags[0].Tfidf).To(BeAssignableToTypeOf(tfidf))
 			})
 		})
+		Context("Weight tags of indonesian.txt by POS", func() {
+			It("Should rank proper names and nouns first", func() {
+				tagger, _ := New(WithLang("id"))
+				tags := terms(tagger.GetTags(string(indonesian), 7))
+				Expect(tags[:3]).To(Equal([]string{"suriah", "harun", "jerman"}))
+				Expect(tags).To(ContainElement("penjara"))
+			})
+			It("Should rank nouns and proper names above adverbs", func() {
+				// keep the stop words, the only adverbs of indonesian.txt are stop words
+				tagger, _ := New(WithLang("id"), WithStopWords([]string{}))
+				tags := terms(tagger.GetTags(string(indonesian), 1000))
+				rank := func(term string) int {
+					for i, tag := range tags {
+						if tag == term {
+							return i
+						}
+					}
+					Fail("missing tag " + term)
+					return -1
+				}
+				for _, adverb := range []string{"juga", "akan"} {
+					for _, term := range []string{"suriah", "harun", "jerman", "penjara", "kelompok"} {
+						Expect(rank(term)).To(BeNumerically("<", rank(adverb)), term+" should outrank "+adverb)
+					}
+				}
+			})
+			It("Should use the modifiers set by the caller", func() {
+				modifiers := DefaultModifiers()
+				modifiers[POSNoun] = 10
+				tagger, _ := New(WithLang("id"), WithModifiers(modifiers))
+				// gabung and penjara are the most frequent nouns
+				tags := terms(tagger.GetTags(string(indonesian), 2))
+				Expect(tags).To(ConsistOf("bergabung", "penjara"))
+			})
+		})
 		Context("Get tags of sample.txt", func() {
 			It("Should return a slice of *Info with length of 5", func() {
 				var term string
@@ -80,10 +121,476 @@ var _ = Describe("Tek", func() {
 				Expect(tags[0].Tfidf).To(BeAssignableToTypeOf(tfidf))
 			})
 		})
+		Context("Get tags with a context", func() {
+			It("Should return ErrEmptyInput for blank text", func() {
+				_, err := GetTagsContext(context.Background(), " \n\t", 5)
+				Expect(err).To(Equal(ErrEmptyInput))
+			})
+			It("Should return ErrInvalidNum if num is not positive", func() {
+				_, err := GetTagsContext(context.Background(), string(sample), 0)
+				Expect(err).To(Equal(ErrInvalidNum))
+			})
+			It("Should return the context error once cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				tags, err := GetTagsContext(ctx, string(sample), 5)
+				Expect(err).To(Equal(context.Canceled))
+				Expect(tags).To(BeNil())
+			})
+			It("Should return the same tags as GetTags", func() {
+				tagger, _ := New(WithLang("en"))
+				tags, err := tagger.GetTagsContext(context.Background(), string(sample), 5)
+				Expect(err).To(BeNil())
+				Expect(terms(tags)).To(Equal(terms(tagger.GetTags(string(sample), 5))))
+			})
+			It("Should not build a tagger with an unsupported language", func() {
+				_, err := New(WithLang("us"))
+				Expect(errors.Is(err, ErrUnsupportedLang)).To(BeTrue())
+			})
+		})
+		Context("Use a corpus IDF model", func() {
+			var model *IDFModel
+			var tagger *Tagger
+
+			BeforeEach(func() {
+				tagger, _ = New(WithLang("en"))
+				model = NewIDFModel()
+				for _, paragraph := range strings.Split(string(sample), "\n\n") {
+					tagger.Train(model, paragraph)
+				}
+			})
+
+			It("Should count each term once per document", func() {
+				Expect(model.Docs()).To(Equal(8))
+				Expect(model.DocFreq("star")).To(Equal(5))
+				Expect(model.DocFreq("unknown")).To(BeZero())
+				Expect(model.IDF("unknown")).To(BeNumerically(">", model.IDF("star")))
+			})
+			It("Should not count texts without words", func() {
+				for _, text := range []string{"", "  \n\t ", "... !?"} {
+					tagger.Train(model, text)
+				}
+				model.AddDocument([]string{"", ""})
+				Expect(model.Docs()).To(Equal(8))
+			})
+			It("Should save and load the model as gob and JSON", func() {
+				for _, format := range []Format{FormatGob, FormatJSON} {
+					var buf bytes.Buffer
+					Expect(model.Save(&buf, format)).To(Succeed())
+					loaded, err := LoadIDFModel(&buf, format)
+					Expect(err).To(BeNil())
+					Expect(loaded.Docs()).To(Equal(model.Docs()))
+					Expect(loaded.DocFreq("star")).To(Equal(model.DocFreq("star")))
+				}
+			})
+			It("Should return ErrUnsupportedFormat for an unknown format", func() {
+				_, err := LoadIDFModel(strings.NewReader("{}"), Format(42))
+				Expect(errors.Is(err, ErrUnsupportedFormat)).To(BeTrue())
+			})
+			It("Should score tags with the corpus IDF", func() {
+				tagger.SetIDFModel(model)
+				tags := tagger.GetTags(string(sample), 5)
+				Expect(tags).To(HaveLen(5))
+				for _, tag := range tags {
+					Expect(tag.Idf).To(Equal(model.IDF(tag.Stem)))
+				}
+			})
+		})
+		Context("Load lists from files", func() {
+			It("Should load stop words one per line", func() {
+				words, err := LoadStopWords(strings.NewReader("# news\nWill\n\n  with \nin  fact\n"))
+				Expect(err).To(BeNil())
+				Expect(words).To(Equal([]string{"will", "with", "in fact"}))
+
+				tagger, _ := New(WithLang("en"))
+				tagger.SetStopWords(append(words, "a", "it", "the", "and", "of"))
+				tags := terms(tagger.GetTags(string(sample), 10))
+				Expect(tags).To(ContainElement("star"))
+				Expect(tags).NotTo(ContainElement("will"))
+			})
+			It("Should load a POS lexicon as JSON, TSV and CSV", func() {
+				file, err := os.Open("pos_id.json")
+				Expect(err).To(BeNil())
+				defer file.Close()
+				pos, err := LoadPOSLexicon(file, FormatJSON)
+				Expect(err).To(BeNil())
+				Expect(pos[0]).To(Equal(&Vocab{Id: 1, Word: "a", Type: "nomina"}))
+
+				want := []*Vocab{{Id: 1, Word: "rumah", Type: POSNoun}, {Id: 2, Word: "makan", Type: POSVerb}}
+				pos, err = LoadPOSLexicon(strings.NewReader("word\ttype\nrumah\tnomina\n# verbs\nMakan\tverba\n"), FormatTSV)
+				Expect(err).To(BeNil())
+				Expect(pos).To(Equal(want))
+				pos, err = LoadPOSLexicon(strings.NewReader("rumah,nomina\n\"makan\",verba\n"), FormatCSV)
+				Expect(err).To(BeNil())
+				Expect(pos).To(Equal(want))
+			})
+			It("Should return an error for a bad row or format", func() {
+				_, err := LoadPOSLexicon(strings.NewReader("rumah\tnomina\nmakan\n"), FormatTSV)
+				Expect(err).To(MatchError(ContainSubstring("row 2")))
+				_, err = LoadPOSLexicon(strings.NewReader(""), FormatGob)
+				Expect(errors.Is(err, ErrUnsupportedFormat)).To(BeTrue())
+			})
+			It("Should feed the loaded lexicon and modifiers to a Tagger", func() {
+				pos, _ := LoadPOSLexicon(strings.NewReader("museum\tnomina\n"), FormatTSV)
+				modifiers, err := LoadModifiers(strings.NewReader(`{"nomina": 100}`))
+				Expect(err).To(BeNil())
+				Expect(modifiers).To(Equal(map[string]float64{POSNoun: 100}))
+
+				tagger, _ := New(WithLang("en"))
+				tagger.SetPOS(pos)
+				tagger.SetModifiers(modifiers)
+				Expect(tagger.GetTags(string(sample), 1)[0].Term).To(Equal("museum"))
+			})
+		})
+		Context("Get phrases of sample.txt", func() {
+			It("Should only return words by default", func() {
+				tagger, _ := New(WithLang("en"))
+				for _, tag := range tagger.GetTags(string(sample), 10) {
+					Expect(tag.Term).ToNot(ContainSubstring(" "))
+				}
+			})
+			It("Should return phrases together with words", func() {
+				tagger, _ := New(WithLang("en"), WithMaxPhraseLen(3))
+				tags := terms(tagger.GetTags(string(sample), 10))
+				Expect(tags).To(ContainElement("star wars"))
+				Expect(tags).To(ContainElement("george lucas"))
+				Expect(tags).To(ContainElement("star"))
+			})
+			It("Should not return the words of a chosen phrase", func() {
+				tagger, _ := New(WithLang("en"), WithMaxPhraseLen(3), WithSuppressComponents(true))
+				tags := terms(tagger.GetTags(string(sample), 10))
+				Expect(tags).To(HaveLen(10))
+				Expect(tags).To(ContainElement("star wars"))
+				Expect(tags).ToNot(ContainElement("star"))
+				Expect(tags).ToNot(ContainElement("wars"))
+			})
+		})
+		Context("Rank tags of sample.txt with TextRank", func() {
+			It("Should return words and merged phrases sorted by score", func() {
+				tagger, _ := New(WithLang("en"), WithAlgorithm(AlgorithmTextRank))
+				tags := tagger.GetTags(string(sample), 10)
+				Expect(tags).To(HaveLen(10))
+				Expect(tags[0].Term).To(Equal("star wars"))
+				Expect(terms(tags)).To(ContainElement("museum"))
+				Expect(terms(tags)).To(ContainElement("george lucas"))
+				for i := 1; i < len(tags); i++ {
+					Expect(tags[i].Score).To(BeNumerically("<=", tags[i-1].Score))
+				}
+			})
+			It("Should merge phrases of up to the max phrase length", func() {
+				text := "The Lucas narrative museum opened in May. Visitors love the Lucas narrative museum. The Lucas narrative museum sells tickets online."
+				// up to 3 words by default
+				long, _ := New(WithLang("en"), WithAlgorithm(AlgorithmTextRank))
+				Expect(terms(long.GetTags(text, 1000))).To(ContainElement("lucas narrative museum"))
+				for _, n := range []int{1, 2} {
+					tagger, _ := New(WithLang("en"), WithAlgorithm(AlgorithmTextRank), WithMaxPhraseLen(n))
+					for _, tag := range tagger.GetTags(text, 1000) {
+						Expect(len(strings.Fields(tag.Stem))).To(BeNumerically("<=", n), tag.Term)
+					}
+				}
+			})
+			It("Should use the window set by the caller", func() {
+				narrow, _ := New(WithLang("en"), WithAlgorithm(AlgorithmTextRank))
+				wide, _ := New(WithLang("en"), WithAlgorithm(AlgorithmTextRank), WithWindow(5))
+				Expect(wide.GetTags(string(sample), 1)[0].Score).ToNot(Equal(narrow.GetTags(string(sample), 1)[0].Score))
+			})
+			It("Should stop once the context is cancelled", func() {
+				tagger, _ := New(WithLang("en"), WithAlgorithm(AlgorithmTextRank))
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				_, err := tagger.GetTagsContext(ctx, string(sample), 5)
+				Expect(err).To(Equal(context.Canceled))
+			})
+		})
+		Context("Extract keywords of sample.txt with RAKE", func() {
+			It("Should return phrases split at stop words and punctuation", func() {
+				tagger, _ := New(WithLang("en"), WithAlgorithm(AlgorithmRAKE))
+				tags := terms(tagger.GetTags(string(sample), 30))
+				Expect(tags).To(ContainElement("pedestrian bridge"))
+				Expect(tags).To(ContainElement("george lucas"))
+				// "will" is a stop word and "Chicago," ends a clause
+				Expect(tags).ToNot(ContainElement(ContainSubstring("will")))
+				Expect(tags).ToNot(ContainElement(HavePrefix("chicago right")))
+			})
+			It("Should join keywords adjoining twice through stop words", func() {
+				tagger, _ := New(WithLang("en"), WithAlgorithm(AlgorithmRAKE))
+				scores := make(map[string]float64)
+				for _, tag := range tagger.GetTags(string(sample), 100) {
+					scores[tag.Term] = tag.Score
+				}
+				Expect(scores).To(HaveKey("museum of narrative art"))
+				Expect(scores["museum of narrative art"]).To(BeNumerically("~", scores["museum"]+scores["narrative art"], 1e-9))
+			})
+		})
+		Context("Score keywords of indonesian.txt with YAKE", func() {
+			It("Should rank capitalized names seen early among the top tags", func() {
+				tagger, _ := New(WithLang("id"), WithAlgorithm(AlgorithmYAKE))
+				tags := terms(tagger.GetTags(string(indonesian), 10))
+				Expect(tags).To(ContainElement("suriah"))
+				Expect(tags).To(ContainElement("harun"))
+			})
+			It("Should use the casing of words", func() {
+				tagger, _ := New(WithLang("id"), WithAlgorithm(AlgorithmYAKE))
+				score := func(text string) float64 {
+					for _, tag := range tagger.GetTags(text, 1000) {
+						if tag.Term == "harun" {
+							return tag.Score
+						}
+					}
+					return 0
+				}
+				Expect(score(string(indonesian))).To(BeNumerically(">", score(strings.ToLower(string(indonesian)))))
+			})
+		})
+		Context("Display tags as written", func() {
+			It("Should keep the casing of names and acronyms", func() {
+				tagger, _ := New(WithLang("en"))
+				display := make(map[string]string)
+				for _, tag := range tagger.GetTags(string(sample), 1000) {
+					display[tag.Term] = tag.Display
+				}
+				Expect(display).To(HaveKeyWithValue("lucas", "Lucas"))
+				Expect(display).To(HaveKeyWithValue("chicago", "Chicago"))
+				Expect(display).To(HaveKeyWithValue("mad", "MAD"))
+				Expect(display).To(HaveKeyWithValue("museum", "museum"))
+			})
+			It("Should display phrases", func() {
+				tagger, _ := New(WithMaxPhraseLen(3), WithAlgorithm(AlgorithmRAKE))
+				display := make(map[string]string)
+				for _, tag := range tagger.GetTags(string(sample), 1000) {
+					display[tag.Term] = tag.Display
+				}
+				Expect(display).To(HaveKeyWithValue("star wars", "Star Wars"))
+				Expect(display).To(HaveKeyWithValue("beijing-based mad architects", "Beijing-based MAD Architects"))
+			})
+		})
+		Context("Locate tags in the text", func() {
+			It("Should return the offsets and sentences of every occurrence", func() {
+				text := string(sample)
+				sentences := SplitSentences(text, "en")
+				tagger, _ := New(WithMaxPhraseLen(2))
+				for _, tag := range tagger.GetTags(text, 10) {
+					Expect(tag.Spans).NotTo(BeEmpty())
+					Expect(tag.Sentences).To(HaveLen(len(tag.Spans)))
+					for i, span := range tag.Spans {
+						written := text[span.Start:span.End]
+						Expect(sentences[tag.Sentences[i]]).To(ContainSubstring(written))
+						if tag.Term == "star wars" {
+							Expect(written).To(Equal("Star Wars"))
+						}
+					}
+				}
+			})
+			It("Should find the occurrences in repeated sentences", func() {
+				text := "Jakarta banjir lagi. Warga mengungsi. Jakarta banjir lagi."
+				tagger, _ := New(WithLang("id"))
+				tags, _ := tagger.GetTagsContext(context.Background(), text, 10)
+				var jakarta *Info
+				for _, tag := range tags {
+					if tag.Term == "jakarta" {
+						jakarta = tag
+					}
+				}
+				Expect(jakarta).NotTo(BeNil())
+				Expect(jakarta.Spans).To(Equal([]Span{{0, 7}, {38, 45}}))
+				Expect(jakarta.Sentences).To(Equal([]int{0, 2}))
+			})
+		})
+		Context("Explain the scores", func() {
+			component := func(e Explanation, name string) *Component {
+				for i := range e {
+					if e[i].Name == name {
+						return &e[i]
+					}
+				}
+				return nil
+			}
+			It("Should list the components of each score", func() {
+				tagger, _ := New(WithLang("id"), WithExplain(true))
+				tags := tagger.GetTags(string(indonesian), 3)
+				Expect(tags[0].Term).To(Equal("suriah"))
+				e := tags[0].Explanation
+				Expect(component(e, "count").Value).To(BeNumerically(">", 1))
+				Expect(component(e, "idf").Detail).To(Equal("document"))
+				Expect(component(e, "pos").Detail).To(Equal(POSName))
+				Expect(component(e, "pos").Factor).To(BeTrue())
+				Expect(component(e, "score").Value).To(Equal(tags[0].Score))
+				Expect(e.String()).To(ContainSubstring("boost pos x"))
+			})
+			It("Should name the corpus as the source of IDF", func() {
+				model := NewIDFModel()
+				tagger, _ := New(WithExplain(true), WithIDFModel(model))
+				tagger.Train(model, string(sample))
+				tags := tagger.GetTags(string(sample), 1)
+				Expect(component(tags[0].Explanation, "idf").Detail).To(Equal("corpus"))
+			})
+			It("Should show penalties", func() {
+				modifiers := DefaultModifiers()
+				modifiers[POSName] = -0.5
+				tagger, _ := New(WithLang("id"), WithExplain(true), WithModifiers(modifiers))
+				tags := tagger.GetTags(string(indonesian), 1000)
+				var suriah *Info
+				for _, tag := range tags {
+					if tag.Term == "suriah" {
+						suriah = tag
+					}
+				}
+				Expect(suriah.Explanation.String()).To(ContainSubstring("penalty pos x0.5 (nama)"))
+			})
+			It("Should not explain by default", func() {
+				Expect(GetTags(string(sample), 1)[0].Explanation).To(BeNil())
+			})
+		})
+		Context("Weight words of several POS types", func() {
+			modifiers := map[string]float64{POSNoun: 10, POSVerb: 0}
+			posOf := func(tagger *Tagger, text string, stem string) *Component {
+				for _, tag := range tagger.GetTags(text, 100) {
+					if tag.Stem == stem {
+						for i := range tag.Explanation {
+							if tag.Explanation[i].Name == "pos" {
+								return &tag.Explanation[i]
+							}
+						}
+						return &Component{Name: "pos", Value: 1}
+					}
+				}
+				Fail("missing tag " + stem)
+				return nil
+			}
+			It("Should blend the types by their frequency", func() {
+				pos := []*Vocab{{Word: "attack", Type: POSVerb, Types: []POSType{{Type: POSVerb, Freq: 3}, {Type: POSNoun, Freq: 1}}}}
+				tagger, _ := New(WithLang("en"), WithPOS(pos), WithModifiers(modifiers), WithExplain(true))
+				c := posOf(tagger, "The attack on the city was the first attack of the war.", "attack")
+				Expect(c.Value).To(BeNumerically("~", 1+0.25*10, 1e-9))
+				Expect(c.Detail).To(Equal("verba 75%/nomina 25%"))
+			})
+			It("Should merge the entries of a word", func() {
+				pos := []*Vocab{{Word: "attack", Type: POSVerb}, {Word: "attack", Type: POSNoun}}
+				tagger, _ := New(WithLang("en"), WithPOS(pos), WithModifiers(modifiers), WithExplain(true))
+				c := posOf(tagger, "The attack on the city was the first attack of the war.", "attack")
+				Expect(c.Value).To(BeNumerically("~", 1+0.5*10, 1e-9))
+			})
+			It("Should choose the type the affixes of an Indonesian word point to", func() {
+				pos := []*Vocab{{Word: "serang", Type: POSVerb, Types: []POSType{{Type: POSVerb, Freq: 3}, {Type: POSNoun, Freq: 1}}}}
+				tagger, _ := New(WithLang("id"), WithPOS(pos), WithModifiers(modifiers), WithExplain(true))
+				noun := posOf(tagger, "Serangan itu terjadi di kota. Kota itu rusak karena serangan udara.", "serang")
+				Expect(noun.Value).To(BeNumerically("~", 11, 1e-9))
+				verb := posOf(tagger, "Pasukan itu menyerang kota. Mereka menyerang lagi pada malam hari.", "serang")
+				Expect(verb.Value).To(BeNumerically("~", 1, 1e-9))
+			})
+			It("Should load the types of a word from several rows", func() {
+				pos, err := LoadPOSLexicon(strings.NewReader("word\ttype\tfreq\nserang\tverba\t3\nrumah\tnomina\nserang\tnomina\t1\n"), FormatTSV)
+				Expect(err).To(BeNil())
+				Expect(pos).To(Equal([]*Vocab{
+					{Id: 1, Word: "serang", Type: POSVerb, Types: []POSType{{Type: POSVerb, Freq: 3}, {Type: POSNoun, Freq: 1}}},
+					{Id: 2, Word: "rumah", Type: POSNoun},
+				}))
+			})
+		})
+		Context("Diversify the tags", func() {
+			text := "The museum opened downtown. Museums in Chicago welcomed the museum crowd. " +
+				"Lucas planned more museums near the lake. The museum drew Lucas fans."
+			It("Should keep near-duplicates without diversity", func() {
+				tagger, _ := New(WithStemmer(nil))
+				Expect(terms(tagger.GetTags(text, 4))).To(ContainElement("museums"))
+			})
+			It("Should leave out near-duplicates with MMR", func() {
+				tagger, _ := New(WithStemmer(nil), WithDiversity(0.5))
+				tags := terms(tagger.GetTags(text, 4))
+				Expect(tags[0]).To(Equal("museum"))
+				Expect(tags).NotTo(ContainElement("museums"))
+				Expect(tags).To(HaveLen(4))
+			})
+			It("Should keep the order of scores with a lambda of 1", func() {
+				tagger, _ := New(WithLang("id"), WithDiversity(1))
+				id, _ := New(WithLang("id"))
+				Expect(terms(tagger.GetTags(string(indonesian), 10))).To(Equal(terms(id.GetTags(string(indonesian), 10))))
+			})
+		})
+		Context("Remove stop phrases", func() {
+			text := "The flight was cancelled due to the storm. In spite of the storm, the airline kept its promise. " +
+				"Due to the delay, passengers got a refund. The storm was due in the evening. " +
+				"The airline said the refund would come in spite of costs."
+			It("Should remove the words of the built in stop phrases", func() {
+				tagger, _ := New(WithLang("en"), WithMaxPhraseLen(2))
+				tags := terms(tagger.GetTags(text, 100))
+				Expect(tags).To(ContainElement("storm"))
+				Expect(tags).NotTo(ContainElement("spite"))
+				// only left where it isn't part of "due to"
+				Expect(tags).To(ContainElement("due"))
+				for _, tag := range tagger.GetTags(text, 100) {
+					if tag.Term == "due" {
+						Expect(tag.Spans).To(HaveLen(1))
+					}
+				}
+			})
+			It("Should match built in phrases written with a capital or an apostrophe", func() {
+				tagger, _ := New(WithLang("en"))
+				text := "I think the harbor needs a new crane. What's more, the harbor needs a new pier. " +
+					"I think the crane will be ready soon, what's more the pier too."
+				tags := terms(tagger.GetTags(text, 100))
+				Expect(tags).To(ContainElement("harbor"))
+				Expect(tags).NotTo(ContainElement("think"))
+				Expect(tags).NotTo(ContainElement("whats"))
+			})
+			It("Should match a capitalized phrase given to SetStopWords", func() {
+				tagger, _ := New(WithLang("en"))
+				tagger.SetStopWords([]string{"the", "was", "a", "in", "of", "its", "would", "Kept Its Promise"})
+				Expect(terms(tagger.GetTags(text, 100))).NotTo(ContainElement("promise"))
+			})
+			It("Should match the phrases given to SetStopWords", func() {
+				tagger, _ := New(WithLang("en"))
+				tagger.SetStopWords([]string{"the", "was", "a", "in", "of", "its", "would", "kept its promise"})
+				tags := terms(tagger.GetTags(text, 100))
+				Expect(tags).NotTo(ContainElement("kept"))
+				Expect(tags).NotTo(ContainElement("promise"))
+				Expect(tags).To(ContainElement("spite"))
+			})
+		})
+		Context("Use separate taggers", func() {
+			It("Should keep the language of each tagger", func() {
+				en, err := New(WithLang("en"))
+				Expect(err).To(BeNil())
+				id, err := New(WithLang("id"))
+				Expect(err).To(BeNil())
+				Expect(en.Lang()).To(Equal("en"))
+				Expect(id.Lang()).To(Equal("id"))
+			})
+			It("Should return the same tags when used concurrently", func() {
+				en, _ := New(WithLang("en"))
+				id, _ := New(WithLang("id"))
+				wantEn := en.GetTags(string(sample), 10)
+				wantId := id.GetTags(string(indonesian), 10)
+
+				done := make(chan bool)
+				for i := 0; i < 8; i++ {
+					go func(i int) {
+						defer GinkgoRecover()
+						if i%2 == 0 {
+							Expect(terms(en.GetTags(string(sample), 10))).To(Equal(terms(wantEn)))
+						} else {
+							Expect(terms(id.GetTags(string(indonesian), 10))).To(Equal(terms(wantId)))
+						}
+						done <- true
+					}(i)
+				}
+				for i := 0; i < 8; i++ {
+					<-done
+				}
+			})
+		})
 	})
 
 })
 
+func terms(tags []*Info) []string {
+	res := make([]string, len(tags))
+	for i, tag := range tags {
+		res[i] = tag.Term
+	}
+	return res
+}
+
 func BenchmarkGetTagEn(b *testing.B) {
 	SetLang("en")
 	for i := 0; i < b.N; i++ {
@@ -97,3 +604,43 @@ func BenchmarkGetTagId(b *testing.B) {
 		GetTags(string(indonesian), 10)
 	}
 }
+
+func BenchmarkGetTags10kWords(b *testing.B) {
+	benchmarkGetTags(b, 10000)
+}
+
+func BenchmarkGetTags100kWords(b *testing.B) {
+	benchmarkGetTags(b, 100000)
+}
+
+func benchmarkGetTags(b *testing.B, words int) {
+	text := generateText(words)
+	tagger, _ := New(WithLang("en"))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tagger.GetTags(text, 10)
+	}
+}
+
+// generateText builds a text of n words picked from sample.txt, with sentences
+// of random length so they don't collapse into duplicates.
+func generateText(n int) string {
+	vocab := strings.Fields(string(sample))
+	for i, word := range vocab {
+		vocab[i] = strings.Trim(word, ".,!?:()")
+	}
+	r := rand.New(rand.NewSource(1))
+	var b strings.Builder
+	sentenceLen := 0
+	for i := 0; i < n; i++ {
+		b.WriteString(vocab[r.Intn(len(vocab))])
+		sentenceLen++
+		if sentenceLen > 5 && r.Intn(10) == 0 {
+			b.WriteString(". ")
+			sentenceLen = 0
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,182 @@
+package tek
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// damping factor of PageRank
+	textRankDamping = 0.85
+	// PageRank stops once no score moves more than this
+	textRankTolerance     = 1e-4
+	textRankMaxIterations = 100
+	// longest phrase merged when WithMaxPhraseLen isn't set
+	textRankMaxPhraseLen = 3
+)
+
+// textRank builds a graph whose vertices are the stems of the words that
+// aren't stop words, linking two of them each time they occur less than
+// window words apart in a sentence. The vertices are ranked with PageRank,
+// then adjacent words of the top third are merged into phrases scored by the
+// sum of their words. Like the phrases of TF-IDF, they must occur at least
+// twice and have at most maxPhraseLen words.
+func textRank(ctx context.Context, doc *document, window, maxPhraseLen int) ([]*Info, error) {
+	// vertices by first appearance, edges weighted by the number of co-occurrences
+	ids := make(map[string]int)
+	var vertices []string
+	var edges []map[int]float64
+	vertex := func(stem string) int {
+		id, ok := ids[stem]
+		if !ok {
+			id = len(vertices)
+			ids[stem] = id
+			vertices = append(vertices, stem)
+			edges = append(edges, make(map[int]float64))
+		}
+		return id
+	}
+	for i, sen := range doc.sentences {
+		var filtered []int
+		for j := range sen {
+			if !doc.stops[i][j] {
+				filtered = append(filtered, vertex(doc.keys[i][j]))
+			}
+		}
+		for j, a := range filtered {
+			for k := j + 1; k < len(filtered) && k-j < window; k++ {
+				if b := filtered[k]; a != b {
+					edges[a][b]++
+					edges[b][a]++
+				}
+			}
+		}
+	}
+
+	scores, err := pageRank(ctx, edges)
+	if err != nil {
+		return nil, err
+	}
+
+	termsInfo := make([]*Info, 0, len(vertices))
+	for id, stem := range vertices {
+		termsInfo = append(termsInfo, newTextRankInfo(doc, stem, doc.form(stem), scores[id]))
+	}
+	return append(termsInfo, textRankPhrases(doc, ids, scores, maxPhraseLen)...), nil
+}
+
+// pageRank runs PageRank over the weighted undirected graph edges until the scores converge.
+func pageRank(ctx context.Context, edges []map[int]float64) ([]float64, error) {
+	n := len(edges)
+	scores := make([]float64, n)
+	next := make([]float64, n)
+	// total weight leaving each vertex
+	out := make([]float64, n)
+	for i, links := range edges {
+		scores[i] = 1
+		for _, w := range links {
+			out[i] += w
+		}
+	}
+
+	for iter := 0; iter < textRankMaxIterations; iter++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		delta := 0.0
+		for i, links := range edges {
+			sum := 0.0
+			for j, w := range links {
+				sum += w / out[j] * scores[j]
+			}
+			next[i] = 1 - textRankDamping + textRankDamping*sum
+			delta = math.Max(delta, math.Abs(next[i]-scores[i]))
+		}
+		scores, next = next, scores
+		if delta < textRankTolerance {
+			break
+		}
+	}
+	return scores, nil
+}
+
+// textRankPhrases merges runs of adjacent top ranked words into phrases,
+// keeping those of at most maxPhraseLen words occurring at least minPhraseFreq times.
+func textRankPhrases(doc *document, ids map[string]int, scores []float64, maxPhraseLen int) []*Info {
+	top := topThird(scores)
+	var stems []string
+	counts := make(map[string]int)
+	for i, sen := range doc.sentences {
+		start := 0
+		for j := 0; j <= len(sen); j++ {
+			eligible := j < len(sen) && !doc.stops[i][j] && top[ids[doc.keys[i][j]]]
+			if eligible && (j == start || !doc.breaks[i][j-1]) {
+				continue
+			}
+			// sen[start:j] is a run of top ranked words
+			if j-start > 1 && j-start <= maxPhraseLen && !hasRepeat(doc.keys[i][start:j]) {
+				stem := strings.Join(doc.keys[i][start:j], " ")
+				if counts[stem] == 0 {
+					stems = append(stems, stem)
+				}
+				counts[stem]++
+			}
+			// a word after a clause boundary starts the next run
+			start = j + 1
+			if eligible {
+				start = j
+			}
+		}
+	}
+
+	var phrases []*Info
+	for _, stem := range stems {
+		count := counts[stem]
+		if count < minPhraseFreq {
+			continue
+		}
+		score := 0.0
+		for _, key := range strings.Fields(stem) {
+			score += scores[ids[key]]
+		}
+		phrases = append(phrases, &Info{Term: doc.form(stem), Stem: stem, Tf: float64(count) / float64(doc.words), Score: score})
+	}
+	return phrases
+}
+
+// topThird marks the vertices ranked in the top third, as done in the TextRank paper.
+func topThird(scores []float64) []bool {
+	top := make([]bool, len(scores))
+	if len(scores) == 0 {
+		return top
+	}
+	sorted := append([]float64(nil), scores...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+	limit := sorted[(len(sorted)-1)/3]
+	for i, score := range scores {
+		top[i] = score >= limit
+	}
+	return top
+}
+
+func newTextRankInfo(doc *document, stem string, form string, score float64) *Info {
+	info := &Info{Term: form, Stem: stem, Kind: doc.kind(stem), Score: score}
+	if stat := doc.index[stem]; stat != nil {
+		info.Tf = float64(stat.freq) / float64(doc.words)
+	}
+	return info
+}
+
+// hasRepeat reports whether a word occurs twice in words, like in "stars star wars".
+func hasRepeat(words []string) bool {
+	for i := range words {
+		for j := i + 1; j < len(words); j++ {
+			if words[i] == words[j] {
+				return true
+			}
+		}
+	}
+	return false
+}
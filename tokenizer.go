@@ -0,0 +1,204 @@
+package tek
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is the kind of a token, and of the tag made from it.
+type Kind int
+
+const (
+	KindWord Kind = iota
+	KindNumber
+	KindHashtag
+	KindMention
+	KindURL
+	KindEmail
+	KindVersion
+)
+
+var kindNames = [...]string{"word", "number", "hashtag", "mention", "url", "email", "version"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "unknown"
+	}
+	return kindNames[k]
+}
+
+// Token is a word found by a Tokenizer.
+type Token struct {
+	// the token as written, without the punctuation around it
+	Text string
+	Kind Kind
+	// byte offsets of the token in the tokenized text
+	Start, End int
+}
+
+// Tokenizer splits a sentence into tokens.
+// Numbers, URLs and emails are never tags, any other token is a candidate.
+type Tokenizer interface {
+	Tokenize(text string) []Token
+}
+
+// TokenizerFunc adapts an ordinary function to a Tokenizer.
+type TokenizerFunc func(text string) []Token
+
+// Tokenize calls f(text).
+func (f TokenizerFunc) Tokenize(text string) []Token {
+	return f(text)
+}
+
+// DefaultTokenizer splits text at spaces and removes the punctuation of each
+// word, keeping the hyphens inside words like "al-Assad" and "COR-TEN". Hashtags,
+// mentions, URLs, emails, versions like "go1.21" and names like "C++" and "C#"
+// are kept whole.
+type DefaultTokenizer struct {
+	// Kinds sets the Kind of hashtags, mentions, URLs, emails and versions,
+	// otherwise they are KindWord like every token but numbers.
+	Kinds bool
+}
+
+// urlPrefixes start a URL.
+var urlPrefixes = []string{"http://", "https://", "www."}
+
+// Runes that may end a URL or an email, but are taken as the punctuation after it.
+const urlTrailers = ".,;:!?)]}\"'”’»"
+
+// Tokenize returns the tokens of text.
+func (tz DefaultTokenizer) Tokenize(text string) []Token {
+	var tokens []Token
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+		end := i + strings.IndexFunc(text[i:], unicode.IsSpace)
+		if end < i {
+			end = len(text)
+		}
+		if tok, ok := tz.token(text, i, end); ok {
+			tokens = append(tokens, tok)
+		}
+		i = end
+	}
+	return tokens
+}
+
+// token makes a token of the field text[start:end].
+func (tz DefaultTokenizer) token(text string, start, end int) (Token, bool) {
+	field := text[start:end]
+
+	// URLs and emails may contain any punctuation
+	for _, prefix := range urlPrefixes {
+		if i := strings.Index(strings.ToLower(field), prefix); i >= 0 && strings.Trim(field[:i], sentenceOpeners) == "" {
+			return tz.whole(text, start+i, start+len(strings.TrimRight(field, urlTrailers)), KindURL), true
+		}
+	}
+	core := strings.TrimRight(strings.TrimLeft(field, sentenceOpeners), urlTrailers)
+	if at := strings.IndexByte(core, '@'); at > 0 && strings.Contains(core[at+1:], ".") && isWordy(core[:at], ".-+") && isWordy(core[at+1:], ".-") {
+		s := start + strings.Index(field, core)
+		return tz.whole(text, s, s+len(core), KindEmail), true
+	}
+
+	// trim the punctuation around the word, but not the '#' and '@' starting a hashtag or mention
+	s := start + strings.IndexFunc(field, func(r rune) bool {
+		return isAlnum(r) || r == '#' || r == '@'
+	})
+	if s < start {
+		return Token{}, false
+	}
+	e := start + strings.LastIndexFunc(field, isAlnum)
+	if e < s {
+		return Token{}, false
+	}
+	_, size := utf8.DecodeRuneInString(text[e:])
+	e += size
+	core = text[s:e]
+
+	switch {
+	case (core[0] == '#' || core[0] == '@') && len(core) > 1 && isWordy(core[1:], "_"):
+		kind := KindHashtag
+		if core[0] == '@' {
+			kind = KindMention
+		}
+		return tz.whole(text, s, e, kind), true
+	case isVersion(core):
+		return tz.whole(text, s, e, KindVersion), true
+	case strings.HasPrefix(text[e:end], "++"):
+		return Token{Text: text[s : e+2], Kind: KindWord, Start: s, End: e + 2}, true
+	case strings.HasPrefix(text[e:end], "#"):
+		return Token{Text: text[s : e+1], Kind: KindWord, Start: s, End: e + 1}, true
+	}
+	if isNumber(core) {
+		return Token{Text: core, Kind: KindNumber, Start: s, End: e}, true
+	}
+	word := cleanWord(core)
+	if word == "" {
+		return Token{}, false
+	}
+	kind := KindWord
+	if isNumeric(word) {
+		kind = KindNumber
+	}
+	return Token{Text: word, Kind: kind, Start: s, End: e}, true
+}
+
+// whole returns text[start:end] as a single token of kind, or of KindWord if kinds aren't set.
+func (tz DefaultTokenizer) whole(text string, start, end int, kind Kind) Token {
+	if !tz.Kinds {
+		kind = KindWord
+	}
+	return Token{Text: text[start:end], Kind: kind, Start: start, End: end}
+}
+
+func isAlnum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isWordy reports whether s is made of letters, digits and the runes in extra.
+func isWordy(s string, extra string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isAlnum(r) && !strings.ContainsRune(extra, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNumber reports whether s is a number like "1.600", "3,14" or "21/1/2015".
+func isNumber(s string) bool {
+	hasDigit := false
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			hasDigit = true
+		} else if !strings.ContainsRune(".,/:-", r) {
+			return false
+		}
+	}
+	return hasDigit
+}
+
+// isVersion reports whether s is a version like "go1.21" or "v2.0.1": letters, then numbers separated by dots.
+func isVersion(s string) bool {
+	i := strings.IndexFunc(s, unicode.IsDigit)
+	if i <= 0 || strings.IndexFunc(s[:i], func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+		return false
+	}
+	parts := strings.Split(s[i:], ".")
+	if len(parts) < 2 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" || strings.IndexFunc(part, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package tek_test
+
+import (
+	"context"
+
+	. "github.com/didasy/tek"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tokenizer", func() {
+
+	texts := func(tokens []Token) []string {
+		res := make([]string, len(tokens))
+		for i, tok := range tokens {
+			res[i] = tok.Text
+		}
+		return res
+	}
+
+	Describe("Using the default tokenizer", func() {
+		It("Should keep special tokens whole", func() {
+			text := "Dukung #PilkadaDKI, kata @jokowi (lihat https://example.com/a?b=1). Kirim ke tim@example.co.id: go1.21, C++ dan C#, al-Assad, COR-TEN."
+			tokens := DefaultTokenizer{Kinds: true}.Tokenize(text)
+			Expect(texts(tokens)).To(Equal([]string{
+				"Dukung", "#PilkadaDKI", "kata", "@jokowi", "lihat", "https://example.com/a?b=1",
+				"Kirim", "ke", "tim@example.co.id", "go1.21", "C++", "dan", "C#", "al-Assad", "COR-TEN",
+			}))
+			kinds := make(map[string]Kind)
+			for _, tok := range tokens {
+				kinds[tok.Text] = tok.Kind
+				Expect(text[tok.Start:tok.End]).To(Equal(tok.Text))
+			}
+			Expect(kinds["#PilkadaDKI"]).To(Equal(KindHashtag))
+			Expect(kinds["@jokowi"]).To(Equal(KindMention))
+			Expect(kinds["https://example.com/a?b=1"]).To(Equal(KindURL))
+			Expect(kinds["tim@example.co.id"]).To(Equal(KindEmail))
+			Expect(kinds["go1.21"]).To(Equal(KindVersion))
+			Expect(kinds["C++"]).To(Equal(KindWord))
+		})
+
+		It("Should mark numbers and only them without Kinds", func() {
+			tokens := DefaultTokenizer{}.Tokenize("#PilkadaDKI 1.600 orang")
+			Expect(texts(tokens)).To(Equal([]string{"#PilkadaDKI", "1.600", "orang"}))
+			Expect(tokens[0].Kind).To(Equal(KindWord))
+			Expect(tokens[1].Kind).To(Equal(KindNumber))
+		})
+	})
+
+	Describe("Tagging with tokens", func() {
+		It("Should return hashtags as tags of their own", func() {
+			tagger, err := New(WithLang("id"), WithMaxPhraseLen(2))
+			Expect(err).To(BeNil())
+			text := "Warga Jakarta memilih gubernur hari ini #PilkadaDKI. Warga Jakarta antre sejak pagi #PilkadaDKI."
+			tags, err := tagger.GetTagsContext(context.Background(), text, 20)
+			Expect(err).To(BeNil())
+			var hashtag *Info
+			for _, tag := range tags {
+				Expect(tag.Term).NotTo(ContainSubstring("pilkadadki "))
+				if tag.Term == "#pilkadadki" {
+					hashtag = tag
+				}
+			}
+			Expect(hashtag).NotTo(BeNil())
+			Expect(hashtag.Kind).To(Equal(KindHashtag))
+			Expect(hashtag.Stem).To(Equal("#pilkadadki"))
+		})
+
+		It("Should use a custom tokenizer", func() {
+			tokenizer := TokenizerFunc(func(text string) []Token {
+				return []Token{{Text: "custom"}, {Text: "token"}}
+			})
+			tagger, err := New(WithTokenizer(tokenizer))
+			Expect(err).To(BeNil())
+			Expect(terms(tagger.GetTags("anything at all", 10))).To(ConsistOf("custom", "token"))
+		})
+	})
+})
@@ -0,0 +1,211 @@
+package tek
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// yakeMaxNgram is the longest keyword YAKE builds, as in the original paper.
+const yakeMaxNgram = 3
+
+// yakeTerm holds the features of a single word.
+type yakeTerm struct {
+	tf float64
+	// occurrences starting with an uppercase letter inside a sentence, and as an acronym
+	tfUpper, tfAcronym float64
+	// distinct and total neighbours on each side
+	left, right           map[string]bool
+	leftCount, rightCount float64
+	score                 float64
+}
+
+// yake scores keywords with a YAKE-like statistical model that needs no corpus.
+// Each word that isn't a stop word gets
+//
+//	S(t) = TRel * TPosition / (TCase + TFNorm/TRel + TSentence/TRel)
+//
+// from its casing, the median index of the sentences it occurs in, its
+// frequency relative to the other words, its spread across sentences and how
+// varied its neighbours are. A keyword of up to three words, which neither
+// starts nor ends with a stop word, gets prod S(t) / (TF * (1 + sum S(t))).
+// Lower is better in YAKE, so Score is the inverse of it.
+func yake(ctx context.Context, doc *document) ([]*Info, error) {
+	terms := make(map[string]*yakeTerm)
+	term := func(stem string) *yakeTerm {
+		t := terms[stem]
+		if t == nil {
+			t = &yakeTerm{left: make(map[string]bool), right: make(map[string]bool)}
+			terms[stem] = t
+		}
+		return t
+	}
+	for i, sen := range doc.sentences {
+		for j := range sen {
+			t := term(doc.keys[i][j])
+			t.tf++
+			surface := doc.surfaces[i][j]
+			switch {
+			case isAcronym(surface):
+				t.tfAcronym++
+			case j > 0 && startsUpper(surface):
+				t.tfUpper++
+			}
+			// neighbours in a window of one word, not across a clause boundary
+			if j > 0 && !doc.breaks[i][j-1] {
+				t.left[doc.keys[i][j-1]] = true
+				t.leftCount++
+			}
+			if j+1 < len(sen) && !doc.breaks[i][j] {
+				t.right[doc.keys[i][j+1]] = true
+				t.rightCount++
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// frequency statistics of the words that aren't stop words
+	var tfs []float64
+	maxTF := 0.0
+	for i, sen := range doc.sentences {
+		for j := range sen {
+			if doc.stops[i][j] {
+				// a stem may be shared by a stop word and a real word, count it once
+				continue
+			}
+			stem := doc.keys[i][j]
+			if t := terms[stem]; t.score == 0 {
+				t.score = -1
+				tfs = append(tfs, t.tf)
+				maxTF = math.Max(maxTF, t.tf)
+			}
+		}
+	}
+	mean, std := meanStd(tfs)
+
+	sentences := float64(len(doc.sentences))
+	for stem, t := range terms {
+		if t.score == 0 {
+			// only seen as a stop word
+			continue
+		}
+		stat := doc.index[stem]
+		tCase := math.Max(t.tfUpper, t.tfAcronym) / (1 + math.Log(t.tf))
+		tPosition := math.Log(math.Log(3 + median(stat.sentences)))
+		tfNorm := t.tf / (mean + std)
+		tRel := 1 + (ratio(len(t.left), t.leftCount)+ratio(len(t.right), t.rightCount))*t.tf/maxTF
+		tSentence := float64(len(stat.sentences)) / sentences
+		t.score = tRel * tPosition / (tCase + tfNorm/tRel + tSentence/tRel)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// candidate keywords, by first appearance
+	var stems []string
+	counts := make(map[string]int)
+	for i, sen := range doc.sentences {
+		for j := range sen {
+			if doc.stops[i][j] {
+				continue
+			}
+			for k := j; k < len(sen) && k-j < yakeMaxNgram; k++ {
+				if k > j && doc.breaks[i][k-1] {
+					break
+				}
+				if doc.stops[i][k] {
+					continue
+				}
+				stem := strings.Join(doc.keys[i][j:k+1], " ")
+				if counts[stem] == 0 {
+					stems = append(stems, stem)
+				}
+				counts[stem]++
+			}
+		}
+	}
+
+	termsInfo := make([]*Info, 0, len(stems))
+	for _, stem := range stems {
+		count := counts[stem]
+		prod, sum := 1.0, 0.0
+		for _, key := range strings.Fields(stem) {
+			// stop words inside a keyword don't change its score
+			if t := terms[key]; t.score > 0 {
+				prod *= t.score
+				sum += t.score
+			}
+		}
+		score := prod / (float64(count) * (1 + sum))
+		termsInfo = append(termsInfo, &Info{
+			Term:  doc.form(stem),
+			Stem:  stem,
+			Kind:  doc.kind(stem),
+			Tf:    float64(count) / float64(doc.words),
+			Score: 1 / score,
+		})
+	}
+	return termsInfo, nil
+}
+
+// isAcronym reports whether word has at least two letters, all uppercase.
+func isAcronym(word string) bool {
+	letters := 0
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters++
+		}
+	}
+	return letters > 1
+}
+
+func startsUpper(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	return unicode.IsUpper(r)
+}
+
+func ratio(a int, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / b
+}
+
+func meanStd(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
+
+func median(sorted []int) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if !sort.IntsAreSorted(sorted) {
+		sorted = append([]int(nil), sorted...)
+		sort.Ints(sorted)
+	}
+	if n%2 == 1 {
+		return float64(sorted[n/2])
+	}
+	return float64(sorted[n/2-1]+sorted[n/2]) / 2
+}